@@ -4,9 +4,11 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"maps"
 	"strings"
 	"unicode"
 
+	"github.com/globocom/go-m3u8/node"
 	pl "github.com/globocom/go-m3u8/playlist"
 	"github.com/globocom/go-m3u8/tags"
 	"github.com/rs/zerolog/log"
@@ -16,37 +18,246 @@ type Source interface {
 	io.ReadCloser
 }
 
+// ParseOption configures how ParsePlaylist reads a playlist.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	lossless bool
+	lenient  *lenientOptions
+}
+
+// Keeps the original manifest lines of each HLS element, so that EncodePlaylist writes the elements that were not changed
+// back verbatim, and the changed ones with their original attribute order and quoting.
+// Blank lines are kept along with the element that follows them.
+func WithLossless() ParseOption {
+	return func(o *parseOptions) {
+		o.lossless = true
+	}
+}
+
+// Decoder reads m3u8 playlists with its own set of tag parsers, so that custom or proprietary tags can be handled
+// without changing the package-level tags.Parsers map, which is shared by every user of the library.
+//
+// Register all parsers before parsing: Register is not safe for concurrent use, while Parse is.
+type Decoder struct {
+	parsers map[string]tags.TagParser
+}
+
+// Returns a new Decoder with a copy of the default tag parsers (tags.Parsers).
+func NewDecoder() *Decoder {
+	return &Decoder{parsers: maps.Clone(tags.Parsers)}
+}
+
+// Registers the parser for the given tag (e.g. "#EXT-X-CUSTOM"), replacing the existing one, and returns the Decoder.
+// Use tags.CommentLineTag and tags.UnknownTagTag to replace the parsers of comments and tags without a parser.
+func (d *Decoder) Register(tag string, parser tags.TagParser) *Decoder {
+	d.parsers[tag] = parser
+	return d
+}
+
+// Reads an m3u8 playlist from the provided source, using the Decoder's tag parsers, and returns a Playlist object.
+func (d *Decoder) Parse(src Source, opts ...ParseOption) (*pl.Playlist, error) {
+	return parsePlaylist(src, d.parsers, opts)
+}
+
 // Reads an m3u8 playlist from the provided source and returns a Playlist object.
-// It scans each line, identifies HLS elements, and applies the appropriate parser.
-func ParsePlaylist(src Source) (*pl.Playlist, error) {
-	playlist := pl.NewPlaylist()
+// It scans each line, identifies HLS elements, and applies the appropriate parser from tags.Parsers.
+func ParsePlaylist(src Source, opts ...ParseOption) (*pl.Playlist, error) {
+	return parsePlaylist(src, tags.Parsers, opts)
+}
+
+func parsePlaylist(src Source, parsers map[string]tags.TagParser, opts []ParseOption) (*pl.Playlist, error) {
+	decoder := newLineDecoder(parsers, opts)
 
 	scanner := bufio.NewScanner(src)
-	defer func() {
-		if err := src.Close(); err != nil {
-			log.Error().Str("service", "go-m3u8/decode.go").Err(err).Msg("error scanning playlist file")
-		}
-	}()
+	defer closeSource(src)
 
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		linePrefix := extractPrefix(line)
-		parser, exists := tags.Parsers[linePrefix]
-		if exists {
-			if err := parser.Parse(line, playlist); err != nil {
-				return nil, fmt.Errorf("error parsing tag %s: %w", linePrefix, err)
+		if err := decoder.decodeLine(scanner.Text()); err != nil {
+			return nil, err
+		}
+	}
+	if err := decoder.scanError(scanner.Err()); err != nil {
+		return nil, err
+	}
+
+	decoder.finish()
+	return decoder.playlist, decoder.diagnosticsError()
+}
+
+// lineDecoder holds the state of a playlist being parsed line by line.
+type lineDecoder struct {
+	playlist *pl.Playlist
+	parsers  map[string]tags.TagParser
+	options  *parseOptions
+
+	// number of the last line read
+	lineNumber int
+
+	// raw lines read since the last inserted node, and the index of the first one that belongs to the next element
+	rawLines     []string
+	elementStart int
+
+	// indexes in rawLines of the lines of the nodes embedded in the pending element (see node.Raw)
+	embeddedLines []int
+
+	// problems found in lenient mode (see WithLenient)
+	diagnostics []Diagnostic
+}
+
+func newLineDecoder(parsers map[string]tags.TagParser, opts []ParseOption) *lineDecoder {
+	options := &parseOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &lineDecoder{
+		playlist:     pl.NewPlaylist(),
+		parsers:      parsers,
+		options:      options,
+		rawLines:     make([]string, 0),
+		elementStart: -1,
+	}
+}
+
+// Parses a single manifest line, applying the appropriate parser from the decoder's parsers.
+// In lenient mode (see WithLenient), invalid lines are reported as diagnostics instead of returned as errors.
+func (d *lineDecoder) decodeLine(text string) error {
+	d.lineNumber++
+	playlist := d.playlist
+	line := strings.TrimSpace(text)
+	linePrefix := extractPrefix(line)
+	tail := playlist.Tail
+	parser, exists := d.parsers[linePrefix]
+	if !exists && strings.HasPrefix(line, "#") {
+		parser, exists = d.parsers[tags.UnknownTagTag]
+	}
+	if exists {
+		if err := parser.Parse(line, playlist); err != nil {
+			kept, err := d.recover(newParseError(d.lineNumber, text, linePrefix, err), tail)
+			if err != nil || !kept {
+				return err
 			}
-		} else {
-			if err := pl.HandleMultiLineHLSElements(line, playlist); err != nil {
-				return nil, fmt.Errorf("error handling multi-line HLS element %q: %w", line, err)
+			exists = playlist.Tail != tail
+		} else if linePrefix == tags.EventCueOutTag && !strings.Contains(line, ":") {
+			d.diagnose(SeverityWarning, d.lineNumber, DiagnosticMissingCueOutDuration, fmt.Sprintf("tag %s has no duration, 0 is assumed", line))
+		}
+	} else {
+		expectsURI := playlist.CurrentSegment != nil || playlist.CurrentStreamInf != nil
+		if err := pl.HandleMultiLineHLSElements(line, playlist); err != nil {
+			kept, err := d.recover(newParseError(d.lineNumber, text, "", err), tail)
+			if err != nil || !kept {
+				return err
 			}
+		} else if !expectsURI && line != "" && !strings.HasPrefix(line, "#") {
+			d.diagnose(SeverityWarning, d.lineNumber, DiagnosticUnexpectedURI, fmt.Sprintf("URI %q does not follow an #EXTINF or #EXT-X-STREAM-INF tag", line))
+		}
+	}
+
+	if !d.options.lossless {
+		return nil
+	}
+
+	d.rawLines = append(d.rawLines, text)
+	if d.elementStart < 0 && (exists || playlist.Tail != tail) {
+		d.elementStart = len(d.rawLines) - 1
+	}
+	if playlist.Tail == tail {
+		return nil
+	}
+
+	// a node inserted while a multi-line element is pending (e.g. between #EXTINF and its URI) is embedded in it:
+	// its line is also kept with the pending element, so that they are encoded back in their original order
+	if playlist.CurrentSegment != nil || playlist.CurrentStreamInf != nil || playlist.CurrentByteRange != nil || playlist.CurrentGap {
+		raw := newRaw(playlist.Tail.HLSElement, []string{text}, 0)
+		raw.Embedded = true
+		playlist.Tail.HLSElement.Raw = raw
+		d.embeddedLines = append(d.embeddedLines, len(d.rawLines)-1)
+		return nil
+	}
+
+	raw := newRaw(playlist.Tail.HLSElement, d.rawLines, d.elementStart)
+	raw.EmbeddedLines = d.embeddedLines
+	playlist.Tail.HLSElement.Raw = raw
+	d.rawLines, d.elementStart, d.embeddedLines = make([]string, 0), -1, nil
+	return nil
+}
+
+// Returns the error of a line that could not be read (e.g. longer than the scanner's buffer), if any.
+// In lenient mode, it is reported as a diagnostic instead, and the lines read so far make up the playlist.
+func (d *lineDecoder) scanError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	parseErr := &ParseError{Line: d.lineNumber + 1, Err: err}
+	if d.options.lenient == nil {
+		return parseErr
+	}
+	d.diagnose(SeverityError, parseErr.Line, DiagnosticUnreadableLine, err.Error())
+	return nil
+}
+
+// Attaches the raw lines read after the last element to it, once the whole playlist was read.
+// Nodes embedded in an element that was never completed (e.g. #EXTINF without URI) keep their own line only.
+func (d *lineDecoder) finish() {
+	if len(d.embeddedLines) > 0 {
+		for current := d.playlist.Tail; current != nil && current.HLSElement.Raw != nil && current.HLSElement.Raw.Embedded; current = current.Prev {
+			current.HLSElement.Raw.Embedded = false
 		}
+		d.rawLines = withoutLines(d.rawLines, d.embeddedLines)
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to parse playlist at line: %q, error: %w", scanner.Text(), err)
+
+	if len(d.rawLines) > 0 && d.playlist.Tail != nil && d.playlist.Tail.HLSElement.Raw != nil {
+		d.playlist.Tail.HLSElement.Raw.Trailing = d.rawLines
 	}
+}
 
-	return playlist, nil
+func closeSource(src Source) {
+	if err := src.Close(); err != nil {
+		log.Error().Str("service", "go-m3u8/decode.go").Err(err).Msg("error scanning playlist file")
+	}
+}
+
+// Returns the Raw data of the given element, parsed from its manifest lines.
+func newRaw(element *node.HLSElement, lines []string, elementStart int) *node.Raw {
+	raw := &node.Raw{
+		Lines:   lines,
+		Leading: elementStart,
+		URI:     element.URI,
+		Attrs:   maps.Clone(element.Attrs),
+	}
+
+	tagLine := strings.TrimSpace(lines[elementStart])
+	if _, attributeList, found := strings.Cut(tagLine, ":"); found {
+		raw.AttrOrder, raw.AttrText, _ = splitAttributeList(attributeList)
+	}
+
+	return raw
+}
+
+// Splits an attribute-list into its attribute keys, in order, and the text of each attribute (e.g. KEY="value").
+// Returns false if the given text is not entirely an attribute-list.
+func splitAttributeList(attributeList string) ([]string, map[string]string, bool) {
+	matches := pl.ParamRegex.FindAllStringSubmatch(attributeList, -1)
+	if len(matches) == 0 {
+		return nil, nil, false
+	}
+
+	order := make([]string, 0, len(matches))
+	text := make(map[string]string, len(matches))
+	full := make([]string, 0, len(matches))
+	for _, kv := range matches {
+		key := strings.ToUpper(kv[1])
+		order = append(order, key)
+		text[key] = kv[0]
+		full = append(full, kv[0])
+	}
+
+	if strings.Join(full, ",") != strings.TrimSpace(attributeList) {
+		return nil, nil, false
+	}
+	return order, text, true
 }
 
 // Lines that start with the character '#' are either comments or tags.
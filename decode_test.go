@@ -112,6 +112,48 @@ func TestVariableDefineParser(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestStartParser(t *testing.T) {
+	// test valid start tag with TIME-OFFSET and PRECISE
+	playlist := "#EXT-X-START:TIME-OFFSET=-12.5,PRECISE=YES"
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, found := p.Find(tags.StartName)
+	assert.True(t, found)
+	assert.Equal(t, "-12.5", node.HLSElement.Attrs["TIME-OFFSET"])
+	assert.Equal(t, "YES", node.HLSElement.Attrs["PRECISE"])
+
+	// test valid start tag with TIME-OFFSET only
+	playlist = "#EXT-X-START:TIME-OFFSET=10"
+	p, err = setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, found = p.Find(tags.StartName)
+	assert.True(t, found)
+	assert.Equal(t, "10", node.HLSElement.Attrs["TIME-OFFSET"])
+
+	// test invalid start tag without TIME-OFFSET
+	playlist = "#EXT-X-START:PRECISE=YES"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid start tag with invalid TIME-OFFSET
+	playlist = "#EXT-X-START:TIME-OFFSET=abc"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid start tag with invalid PRECISE
+	playlist = "#EXT-X-START:TIME-OFFSET=10,PRECISE=MAYBE"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid playlist with more than one start tag
+	playlist = `#EXT-X-START:TIME-OFFSET=10
+							#EXT-X-START:TIME-OFFSET=20`
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+}
+
 func TestTargetDurationParser(t *testing.T) {
 	playlist := "#EXT-X-TARGETDURATION:7"
 	p, err := setupPlaylist(playlist)
@@ -122,6 +164,236 @@ func TestTargetDurationParser(t *testing.T) {
 	assert.Equal(t, "7", node.HLSElement.Attrs["#EXT-X-TARGETDURATION"])
 }
 
+func TestEndlistParser(t *testing.T) {
+	playlist := "#EXT-X-ENDLIST"
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, ok := p.Find(tags.EndlistName)
+	assert.True(t, ok)
+	assert.Equal(t, "", node.HLSElement.Attrs["#EXT-X-ENDLIST"])
+}
+
+func TestPlaylistTypeParser(t *testing.T) {
+	playlist := "#EXT-X-PLAYLIST-TYPE:VOD"
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, ok := p.Find(tags.PlaylistTypeName)
+	assert.True(t, ok)
+	assert.Equal(t, "VOD", node.HLSElement.Attrs["#EXT-X-PLAYLIST-TYPE"])
+
+	playlist = "#EXT-X-PLAYLIST-TYPE:EVENT"
+	p, err = setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, ok = p.Find(tags.PlaylistTypeName)
+	assert.True(t, ok)
+	assert.Equal(t, "EVENT", node.HLSElement.Attrs["#EXT-X-PLAYLIST-TYPE"])
+
+	// test invalid playlist type value
+	playlist = "#EXT-X-PLAYLIST-TYPE:LIVE"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid playlist type tag without value
+	playlist = "#EXT-X-PLAYLIST-TYPE:"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+}
+
+func TestPartInfParser(t *testing.T) {
+	playlist := "#EXT-X-PART-INF:PART-TARGET=1.004"
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, ok := p.Find(tags.PartInfName)
+	assert.True(t, ok)
+	assert.Equal(t, "1.004", node.HLSElement.Attrs["PART-TARGET"])
+
+	// test invalid part inf tag without PART-TARGET
+	playlist = "#EXT-X-PART-INF:"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+}
+
+func TestServerControlParser(t *testing.T) {
+	playlist := "#EXT-X-SERVER-CONTROL:CAN-BLOCK-RELOAD=YES,CAN-SKIP-UNTIL=24.0,CAN-SKIP-DATERANGES=YES,PART-HOLD-BACK=3.012"
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, ok := p.Find(tags.ServerControlName)
+	assert.True(t, ok)
+	assert.Equal(t, "YES", node.HLSElement.Attrs["CAN-BLOCK-RELOAD"])
+	assert.Equal(t, "24.0", node.HLSElement.Attrs["CAN-SKIP-UNTIL"])
+	assert.Equal(t, "YES", node.HLSElement.Attrs["CAN-SKIP-DATERANGES"])
+	assert.Equal(t, "3.012", node.HLSElement.Attrs["PART-HOLD-BACK"])
+
+	// test invalid server control tag with non-numeric HOLD-BACK
+	playlist = "#EXT-X-SERVER-CONTROL:HOLD-BACK=abc"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid server control tag with CAN-SKIP-DATERANGES but without CAN-SKIP-UNTIL
+	playlist = "#EXT-X-SERVER-CONTROL:CAN-SKIP-DATERANGES=YES"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+}
+
+func TestByteRangeParser(t *testing.T) {
+	playlist := `#EXTINF:6.0,
+							#EXT-X-BYTERANGE:1498352@719
+							main.mp4
+							#EXTINF:6.0,
+							#EXT-X-BYTERANGE:1327488
+							main.mp4`
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Nil(t, p.CurrentByteRange)
+
+	segments := p.Segments()
+	assert.Len(t, segments, 2)
+	assert.Equal(t, "1498352@719", segments[0].HLSElement.Attrs["ByteRange"])
+	assert.Equal(t, "1498352", segments[0].HLSElement.Details["ByteRangeLength"])
+	assert.Equal(t, "719", segments[0].HLSElement.Details["ByteRangeOffset"])
+	assert.Equal(t, "1327488", segments[1].HLSElement.Attrs["ByteRange"])
+	assert.Equal(t, "1327488", segments[1].HLSElement.Details["ByteRangeLength"])
+	assert.Equal(t, "1499071", segments[1].HLSElement.Details["ByteRangeOffset"])
+
+	// test invalid byte range tag
+	playlist = "#EXT-X-BYTERANGE:abc@0"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid byte range without offset and without a previous sub-range
+	playlist = `#EXTINF:6.0,
+							#EXT-X-BYTERANGE:1327488
+							main.mp4`
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid byte range without offset and with a previous sub-range of another resource
+	playlist = `#EXTINF:6.0,
+							#EXT-X-BYTERANGE:1498352@719
+							main.mp4
+							#EXTINF:6.0,
+							#EXT-X-BYTERANGE:1327488
+							other.mp4`
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+}
+
+func TestGapParser(t *testing.T) {
+	playlist := `#EXTINF:4.8,
+							segment1.ts
+							#EXT-X-GAP
+							#EXTINF:4.8,
+							segment2.ts
+							#EXTINF:4.8,
+							segment3.ts`
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+	assert.False(t, p.CurrentGap)
+
+	segments := p.Segments()
+	assert.Len(t, segments, 3)
+	assert.Equal(t, "", segments[0].HLSElement.Attrs["Gap"])
+	assert.Equal(t, "YES", segments[1].HLSElement.Attrs["Gap"])
+	assert.Equal(t, "", segments[2].HLSElement.Attrs["Gap"])
+	assert.Equal(t, 3, p.SegmentsCounter)
+}
+
+func TestPartParser(t *testing.T) {
+	playlist := "#EXT-X-PART:DURATION=1.00002,URI=\"filePart271.1.mp4\",INDEPENDENT=YES,BYTERANGE=\"20000@0\",GAP=YES"
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, ok := p.Find(tags.PartName)
+	assert.True(t, ok)
+	assert.Equal(t, "1.00002", node.HLSElement.Attrs["DURATION"])
+	assert.Equal(t, "filePart271.1.mp4", node.HLSElement.Attrs["URI"])
+	assert.Equal(t, "YES", node.HLSElement.Attrs["INDEPENDENT"])
+	assert.Equal(t, "20000@0", node.HLSElement.Attrs["BYTERANGE"])
+	assert.Equal(t, "YES", node.HLSElement.Attrs["GAP"])
+
+	// test invalid part tag without DURATION
+	playlist = "#EXT-X-PART:URI=\"filePart271.1.mp4\""
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid part tag without URI
+	playlist = "#EXT-X-PART:DURATION=1.00002"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+}
+
+func TestPreloadHintParser(t *testing.T) {
+	playlist := "#EXT-X-PRELOAD-HINT:TYPE=PART,URI=\"filePart272.c.mp4\",BYTERANGE-START=20000,BYTERANGE-LENGTH=5000"
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, ok := p.Find(tags.PreloadHintName)
+	assert.True(t, ok)
+	assert.Equal(t, "PART", node.HLSElement.Attrs["TYPE"])
+	assert.Equal(t, "filePart272.c.mp4", node.HLSElement.Attrs["URI"])
+	assert.Equal(t, "20000", node.HLSElement.Attrs["BYTERANGE-START"])
+	assert.Equal(t, "5000", node.HLSElement.Attrs["BYTERANGE-LENGTH"])
+
+	// test invalid preload hint tag with invalid TYPE
+	playlist = "#EXT-X-PRELOAD-HINT:TYPE=SEGMENT,URI=\"filePart272.c.mp4\""
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid preload hint tag without URI
+	playlist = "#EXT-X-PRELOAD-HINT:TYPE=MAP"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+}
+
+func TestSkipParser(t *testing.T) {
+	playlist := "#EXT-X-SKIP:SKIPPED-SEGMENTS=4,RECENTLY-REMOVED-DATERANGES=\"chapter-0\tchapter-1\""
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, ok := p.Find(tags.SkipName)
+	assert.True(t, ok)
+	assert.Equal(t, "4", node.HLSElement.Attrs["SKIPPED-SEGMENTS"])
+	assert.Equal(t, "chapter-0\tchapter-1", node.HLSElement.Attrs["RECENTLY-REMOVED-DATERANGES"])
+	assert.Equal(t, 4, p.SegmentsCounter)
+
+	// test invalid skip tag without SKIPPED-SEGMENTS
+	playlist = "#EXT-X-SKIP:RECENTLY-REMOVED-DATERANGES=\"chapter-0\""
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid playlist with more than one skip tag
+	playlist = "#EXT-X-SKIP:SKIPPED-SEGMENTS=4\n#EXT-X-SKIP:SKIPPED-SEGMENTS=2"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+}
+
+func TestRenditionReportParser(t *testing.T) {
+	playlist := "#EXT-X-RENDITION-REPORT:URI=\"../1M/waitForMSN.php\",LAST-MSN=273,LAST-PART=2"
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, ok := p.Find(tags.RenditionReportName)
+	assert.True(t, ok)
+	assert.Equal(t, "../1M/waitForMSN.php", node.HLSElement.Attrs["URI"])
+	assert.Equal(t, "273", node.HLSElement.Attrs["LAST-MSN"])
+	assert.Equal(t, "2", node.HLSElement.Attrs["LAST-PART"])
+
+	// test invalid rendition report tag without URI
+	playlist = "#EXT-X-RENDITION-REPORT:LAST-MSN=273,LAST-PART=2"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid rendition report tag with non-numeric LAST-MSN
+	playlist = "#EXT-X-RENDITION-REPORT:URI=\"../1M/waitForMSN.php\",LAST-MSN=abc"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+}
+
 func TestUspTimestampMapParser(t *testing.T) {
 	playlist := "#USP-X-TIMESTAMP-MAP:MPEGTS=900000,LOCAL=2025-01-01T12:34:56Z"
 	p, err := setupPlaylist(playlist)
@@ -183,6 +455,8 @@ func TestMapParser(t *testing.T) {
 	assert.True(t, found)
 	assert.Equal(t, "hls/main.mp4", node.HLSElement.Attrs["URI"])
 	assert.Equal(t, "560@0", node.HLSElement.Attrs["BYTERANGE"])
+	assert.Equal(t, "560", node.HLSElement.Details["ByteRangeLength"])
+	assert.Equal(t, "0", node.HLSElement.Details["ByteRangeOffset"])
 
 	// test valid ext map tag with URI and no BYTERANGE
 	playlist = "#EXT-X-MAP:URI=\"hls/channel-hevc-hdr-video=18000000.m4s\""
@@ -197,6 +471,11 @@ func TestMapParser(t *testing.T) {
 	playlist = "#EXT-X-MAP:BYTERANGE=\"560@0\""
 	_, err = setupPlaylist(playlist)
 	assert.Error(t, err)
+
+	// test invalid map tag with invalid BYTERANGE
+	playlist = "#EXT-X-MAP:URI=\"hls/main.mp4\",BYTERANGE=\"560@abc\""
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
 }
 
 func TestDateRangeParser(t *testing.T) {
@@ -261,6 +540,11 @@ func TestExtInfParser(t *testing.T) {
 
 	assert.Equal(t, 4.8, p.CurrentSegment.Duration)
 	assert.Equal(t, " no desc", p.CurrentSegment.Title)
+
+	// test invalid segment duration
+	playlist = "#EXTINF:invalid, no desc"
+	_, err = setupPlaylist(playlist)
+	assert.ErrorContains(t, err, "error parsing tag #EXTINF: invalid segment duration invalid")
 }
 
 func TestStreamInfParser(t *testing.T) {
@@ -271,6 +555,38 @@ func TestStreamInfParser(t *testing.T) {
 	assert.Equal(t, "300000", p.CurrentStreamInf.Bandwidth)
 	assert.Equal(t, []string{"avc1.42c00a"}, p.CurrentStreamInf.Codecs)
 	assert.Equal(t, "1280x720", p.CurrentStreamInf.Resolution)
+
+	// test stream inf tag with content steering attributes
+	playlist = "#EXT-X-STREAM-INF:BANDWIDTH=300000,CODECS=\"avc1.42c00a\",PATHWAY-ID=\"CDN-A\",STABLE-VARIANT-ID=\"720p\""
+	p, err = setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "CDN-A", p.CurrentStreamInf.Attrs["PATHWAY-ID"])
+	assert.Equal(t, "720p", p.CurrentStreamInf.Attrs["STABLE-VARIANT-ID"])
+	assert.Equal(t, map[string]bool{"BANDWIDTH": false, "CODECS": true, "PATHWAY-ID": true, "STABLE-VARIANT-ID": true}, p.CurrentStreamInf.QuotedAttrs)
+
+	// test stream inf node keeps all attributes
+	playlist = `#EXT-X-STREAM-INF:BANDWIDTH=6800000,SCORE=2.5,CODECS="hvc1.2.4.L150.B0",SUPPLEMENTAL-CODECS="dvh1.08.07/db4h",ALLOWED-CPC="com.example.drm:SMART-TV/PC",REQ-VIDEO-LAYOUT="CH-MONO",PROGRAM-ID=1,X-VENDOR-LABEL="uhd"
+							video/2160p-dv.m3u8`
+	p, err = setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, found := p.Find(tags.StreamInfName)
+	assert.True(t, found)
+	assert.Equal(t, "video/2160p-dv.m3u8", node.HLSElement.URI)
+	assert.Equal(t, map[string]string{
+		"BANDWIDTH":           "6800000",
+		"SCORE":               "2.5",
+		"CODECS":              "hvc1.2.4.L150.B0",
+		"SUPPLEMENTAL-CODECS": "dvh1.08.07/db4h",
+		"ALLOWED-CPC":         "com.example.drm:SMART-TV/PC",
+		"REQ-VIDEO-LAYOUT":    "CH-MONO",
+		"PROGRAM-ID":          "1",
+		"X-VENDOR-LABEL":      "uhd",
+	}, node.HLSElement.Attrs)
+	assert.True(t, node.HLSElement.QuotedAttrs["X-VENDOR-LABEL"])
+	assert.False(t, node.HLSElement.QuotedAttrs["PROGRAM-ID"])
+	assert.Nil(t, node.HLSElement.Details)
 }
 
 func TestMediaParser(t *testing.T) {
@@ -366,6 +682,90 @@ func TestSessionKeyParser(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestSessionDataParser(t *testing.T) {
+	// test valid session data tag with VALUE and LANGUAGE
+	playlist := "#EXT-X-SESSION-DATA:DATA-ID=\"com.globo.title\",VALUE=\"Jornal Nacional\",LANGUAGE=\"pt\""
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, found := p.Find(tags.SessionDataName)
+	assert.True(t, found)
+	assert.Equal(t, "com.globo.title", node.HLSElement.Attrs["DATA-ID"])
+	assert.Equal(t, "Jornal Nacional", node.HLSElement.Attrs["VALUE"])
+	assert.Equal(t, "pt", node.HLSElement.Attrs["LANGUAGE"])
+
+	// test valid session data tag with URI and FORMAT
+	playlist = "#EXT-X-SESSION-DATA:DATA-ID=\"com.globo.chapters\",URI=\"chapters.json\",FORMAT=JSON"
+	p, err = setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, found = p.Find(tags.SessionDataName)
+	assert.True(t, found)
+	assert.Equal(t, "com.globo.chapters", node.HLSElement.Attrs["DATA-ID"])
+	assert.Equal(t, "chapters.json", node.HLSElement.Attrs["URI"])
+	assert.Equal(t, "JSON", node.HLSElement.Attrs["FORMAT"])
+
+	// test valid session data tags with the same DATA-ID and different LANGUAGE
+	playlist = `#EXT-X-SESSION-DATA:DATA-ID="com.globo.title",VALUE="Jornal Nacional",LANGUAGE="pt"
+							#EXT-X-SESSION-DATA:DATA-ID="com.globo.title",VALUE="National News",LANGUAGE="en"`
+	p, err = setupPlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Len(t, p.SessionData(), 2)
+
+	// test invalid session data tag without DATA-ID
+	playlist = "#EXT-X-SESSION-DATA:VALUE=\"Jornal Nacional\""
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid session data tag without VALUE and URI
+	playlist = "#EXT-X-SESSION-DATA:DATA-ID=\"com.globo.title\",LANGUAGE=\"pt\""
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid session data tag with both VALUE and URI
+	playlist = "#EXT-X-SESSION-DATA:DATA-ID=\"com.globo.title\",VALUE=\"Jornal Nacional\",URI=\"title.json\""
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid session data tag with FORMAT and without URI
+	playlist = "#EXT-X-SESSION-DATA:DATA-ID=\"com.globo.title\",VALUE=\"Jornal Nacional\",FORMAT=RAW"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid session data tag with invalid FORMAT
+	playlist = "#EXT-X-SESSION-DATA:DATA-ID=\"com.globo.chapters\",URI=\"chapters.xml\",FORMAT=XML"
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid session data tags with the same DATA-ID and LANGUAGE
+	playlist = `#EXT-X-SESSION-DATA:DATA-ID="com.globo.title",VALUE="Jornal Nacional",LANGUAGE="pt"
+							#EXT-X-SESSION-DATA:DATA-ID="com.globo.title",VALUE="Jornal da Globo",LANGUAGE="pt"`
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+}
+
+func TestContentSteeringParser(t *testing.T) {
+	playlist := "#EXT-X-CONTENT-STEERING:SERVER-URI=\"https://steering.example.com/manifest.json\",PATHWAY-ID=\"CDN-A\""
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	node, found := p.Find(tags.ContentSteeringName)
+	assert.True(t, found)
+	assert.Equal(t, "https://steering.example.com/manifest.json", node.HLSElement.Attrs["SERVER-URI"])
+	assert.Equal(t, "CDN-A", node.HLSElement.Attrs["PATHWAY-ID"])
+
+	// test invalid content steering tag without SERVER-URI
+	playlist = "#EXT-X-CONTENT-STEERING:PATHWAY-ID=\"CDN-A\""
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+
+	// test invalid playlist with more than one content steering tag
+	playlist = `#EXT-X-CONTENT-STEERING:SERVER-URI="https://steering.example.com/manifest.json"
+							#EXT-X-CONTENT-STEERING:SERVER-URI="https://steering.example.com/other.json"`
+	_, err = setupPlaylist(playlist)
+	assert.Error(t, err)
+}
+
 func TestCommentParser(t *testing.T) {
 	playlist := `#EXTM3U
 							#EXT-X-VERSION:4
@@ -382,6 +782,43 @@ func TestCommentParser(t *testing.T) {
 	assert.Equal(t, "# AUDIO groups", nodes[1].HLSElement.Attrs["Comment"])
 }
 
+func TestUnknownTagParser(t *testing.T) {
+	playlist := `#EXTM3U
+							#EXT-X-VERSION:4
+							#EXT-OATCLS-SCTE35:/DAlAAAAAAAAAP/wFAUAAAABf+/+ANgNkv4AFJlwAAEBAQAAXOtPgw==
+							#EXTINF:4.8, no desc
+							#EXT-X-CUE-OUT-CONT:ElapsedTime=4.8,Duration=20
+							1.ts
+							#ext-x-lowercase-tag
+							#EXTINF:4.8, no desc
+
+							2.ts`
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	nodes := p.UnknownTags()
+	assert.Len(t, nodes, 3)
+	assert.Equal(t, "#EXT-OATCLS-SCTE35:/DAlAAAAAAAAAP/wFAUAAAABf+/+ANgNkv4AFJlwAAEBAQAAXOtPgw==", nodes[0].HLSElement.Attrs["UnknownTag"])
+	assert.Equal(t, "#EXT-X-CUE-OUT-CONT:ElapsedTime=4.8,Duration=20", nodes[1].HLSElement.Attrs["UnknownTag"])
+	assert.Equal(t, "#ext-x-lowercase-tag", nodes[2].HLSElement.Attrs["UnknownTag"])
+
+	// tags and blank lines between #EXTINF and its URI are never taken as the segment URI
+	segments := p.Segments()
+	assert.Len(t, segments, 2)
+	assert.Equal(t, "1.ts", segments[0].HLSElement.URI)
+	assert.Equal(t, "2.ts", segments[1].HLSElement.URI)
+
+	// in lossless mode, the tags between #EXTINF and its URI are embedded in the segment and keep their place
+	p, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(playlist)), m3u8.WithLossless())
+	assert.NoError(t, err)
+	assert.True(t, p.UnknownTags()[1].HLSElement.Raw.Embedded)
+	assert.Equal(t, []int{1}, p.Segments()[0].HLSElement.Raw.EmbeddedLines)
+
+	encoded, err := m3u8.EncodePlaylist(p)
+	assert.NoError(t, err)
+	assert.Equal(t, playlist+"\n", encoded)
+}
+
 func TestMultiLineHLSElements_Segments(t *testing.T) {
 	playlist := `#EXTINF:4.8, no desc
               1.ts`
@@ -852,3 +1289,189 @@ func TestMediaPlaylist_WithIFramesOnly(t *testing.T) {
 	assert.Equal(t, "hls/channel-hevc-hdr-video=18000000.m4s", mapTag.HLSElement.Attrs["URI"])
 	assert.Contains(t, segment.HLSElement.URI, ".m4s")
 }
+
+func TestParseMediaPlaylist_WithPlaylistTypeVOD(t *testing.T) {
+	file, _ := os.Open("mocks/media/withPlaylistTypeVOD.m3u8")
+	p, err := m3u8.ParsePlaylist(file)
+	validatePlaylist(t, p, err)
+
+	breaks := p.Breaks()
+
+	assert.True(t, p.IsVOD())
+	assert.Equal(t, tags.EndlistName, p.Tail.HLSElement.Name)
+	assert.Len(t, p.Segments(), 5)
+	assert.Len(t, breaks, 1)
+	assert.Equal(t, "0", breaks[0].HLSElement.Details["StartMediaSequence"])
+	assert.Equal(t, tags.BreakStatusComplete, breaks[0].HLSElement.Details["Status"])
+}
+
+func TestParseMediaPlaylist_WithEndlist(t *testing.T) {
+	// the break would be leaving the DVR limit on a live playlist, but the playlist has ended
+	file, _ := os.Open("mocks/media/withEndlist.m3u8")
+	p, err := m3u8.ParsePlaylist(file)
+	validatePlaylist(t, p, err)
+
+	breaks := p.Breaks()
+
+	assert.True(t, p.IsVOD())
+	assert.Len(t, breaks, 1)
+	assert.Equal(t, "363991006", breaks[0].HLSElement.Details["StartMediaSequence"])
+	assert.Equal(t, tags.BreakStatusComplete, breaks[0].HLSElement.Details["Status"])
+}
+
+func TestParseMediaPlaylist_WithEndlistAndBreakStartOutsideWindow(t *testing.T) {
+	// the break's first segment left the window before the playlist ended, so no remaining segment starts the break
+	playlist := `#EXTM3U
+				#EXT-X-VERSION:3
+				#EXT-X-TARGETDURATION:5
+				#EXT-X-MEDIA-SEQUENCE:100
+				#EXT-X-PROGRAM-DATE-TIME:2025-07-01T10:00:00Z
+				#EXT-X-DATERANGE:ID="break",START-DATE="2025-07-01T09:59:50Z",PLANNED-DURATION=30,SCTE35-OUT=0xFC
+				#EXTINF:5,
+				segment-100.ts
+				#EXTINF:5,
+				segment-101.ts
+				#EXT-X-ENDLIST`
+	p, err := setupPlaylist(playlist)
+	validatePlaylist(t, p, err)
+
+	breaks := p.Breaks()
+	assert.Len(t, breaks, 1)
+	assert.Equal(t, "0", breaks[0].HLSElement.Details["StartMediaSequence"])
+	assert.Equal(t, tags.BreakStatusLeavingDVR, breaks[0].HLSElement.Details["Status"])
+	assert.Len(t, breaks[0].HLSElement.Details, 2)
+}
+
+func TestParseMediaPlaylist_WithLowLatencyParts(t *testing.T) {
+	file, _ := os.Open("mocks/media/lowlatency/withParts.m3u8")
+	p, err := m3u8.ParsePlaylist(file)
+	validatePlaylist(t, p, err)
+
+	segments := p.Segments()
+	parts := p.FindAll(tags.PartName)
+	partInf, found := p.Find(tags.PartInfName)
+
+	assert.True(t, found)
+	assert.Equal(t, "1.004", partInf.HLSElement.Attrs["PART-TARGET"])
+	assert.Len(t, segments, 6)
+	assert.Len(t, parts, 10)
+	assert.Equal(t, "270", parts[0].HLSElement.Details["MediaSequence"])
+	assert.Equal(t, "0", parts[0].HLSElement.Details["PartIndex"])
+	assert.Equal(t, "271", parts[5].HLSElement.Details["MediaSequence"])
+	assert.Equal(t, "1", parts[5].HLSElement.Details["PartIndex"])
+	assert.Equal(t, "272", parts[9].HLSElement.Details["MediaSequence"])
+	assert.Equal(t, tags.PreloadHintName, p.Tail.HLSElement.Name)
+}
+
+func TestParseMediaPlaylist_WithSkip(t *testing.T) {
+	file, _ := os.Open("mocks/media/lowlatency/withSkip.m3u8")
+	p, err := m3u8.ParsePlaylist(file)
+	validatePlaylist(t, p, err)
+
+	segments := p.Segments()
+
+	assert.Len(t, segments, 3)
+	assert.Equal(t, 7, p.SegmentsCounter)
+	assert.Equal(t, "270", segments[0].HLSElement.Details["MediaSequence"])
+	assert.Equal(t, "272", segments[2].HLSElement.Details["MediaSequence"])
+}
+
+func TestParsePlaylist_Lossless(t *testing.T) {
+	file, _ := os.Open("./mocks/media/withNonCanonicalFormatting.m3u8")
+	p, err := m3u8.ParsePlaylist(file, m3u8.WithLossless())
+	assert.NoError(t, err)
+
+	// blank lines are kept with the node that follows them
+	node, found := p.Find(tags.ProgramDateTimeName)
+	assert.True(t, found)
+	assert.Equal(t, []string{"", "#EXT-X-PROGRAM-DATE-TIME:2025-06-30T19:28:00.100000Z"}, node.HLSElement.Raw.Lines)
+	assert.Equal(t, 1, node.HLSElement.Raw.Leading)
+	assert.True(t, node.HLSElement.Unchanged())
+
+	// attribute-list tags keep their attribute order and text
+	node, found = p.Find(tags.KeyName)
+	assert.True(t, found)
+	assert.Equal(t, []string{"URI", "METHOD", "KEYFORMAT", "IV"}, node.HLSElement.Raw.AttrOrder)
+	assert.Equal(t, `URI="https://example.com/keys/key1.bin"`, node.HLSElement.Raw.AttrText["URI"])
+
+	// multi-line elements keep all their lines, and unknown tags keep their own line
+	segments := p.Segments()
+	assert.Equal(t, []string{"#EXTINF:4.8, no desc", "channel-audio_1=96000-video=789952-364856601.ts"}, segments[0].HLSElement.Raw.Lines)
+	assert.Equal(t, []string{"#EXTINF:4.8, no desc", "channel-audio_1=96000-video=789952-364856602.ts"}, segments[1].HLSElement.Raw.Lines)
+	assert.Equal(t, []string{"#EXT-X-CUE-OUT-CONT:ElapsedTime=4.8,Duration=20"}, p.UnknownTags()[0].HLSElement.Raw.Lines)
+
+	// lines after the last node are kept as trailing lines
+	assert.Equal(t, []string{""}, p.Tail.HLSElement.Raw.Trailing)
+
+	// changes are detected against the parsed attributes and URI
+	segments[0].HLSElement.URI = "replaced.ts"
+	assert.False(t, segments[0].HLSElement.Unchanged())
+	node.HLSElement.Attrs["IV"] = "0x0"
+	assert.False(t, node.HLSElement.Unchanged())
+
+	// raw lines are not kept by default
+	file, _ = os.Open("./mocks/media/withNonCanonicalFormatting.m3u8")
+	p, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+	assert.Nil(t, p.Head.HLSElement.Raw)
+	assert.False(t, p.Head.HLSElement.Unchanged())
+}
+
+type customTagParser struct {
+	name string
+}
+
+func (p customTagParser) Parse(tag string, playlist *pl.Playlist) error {
+	_, value, _ := strings.Cut(tag, ":")
+	playlist.Insert(playlist.NewNode(p.name, "", map[string]string{"VALUE": value}, nil))
+	return nil
+}
+
+func TestDecoder(t *testing.T) {
+	manifest := `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-CUSTOM:42
+#EXTINF:4.8, no desc
+1.ts`
+
+	t.Run("registered parser", func(t *testing.T) {
+		t.Parallel()
+
+		decoder := m3u8.NewDecoder().Register("#EXT-X-CUSTOM", customTagParser{name: "Custom"})
+		p, err := decoder.Parse(io.NopCloser(strings.NewReader(manifest)))
+		assert.NoError(t, err)
+
+		node, found := p.Find("Custom")
+		assert.True(t, found)
+		assert.Equal(t, "42", node.HLSElement.Attrs["VALUE"])
+		assert.Len(t, p.Segments(), 1)
+	})
+
+	t.Run("same tag with another parser", func(t *testing.T) {
+		t.Parallel()
+
+		decoder := m3u8.NewDecoder().Register("#EXT-X-CUSTOM", customTagParser{name: "Other"})
+		p, err := decoder.Parse(io.NopCloser(strings.NewReader(manifest)))
+		assert.NoError(t, err)
+
+		_, found := p.Find("Custom")
+		assert.False(t, found)
+		node, found := p.Find("Other")
+		assert.True(t, found)
+		assert.Equal(t, "42", node.HLSElement.Attrs["VALUE"])
+	})
+
+	t.Run("default parsers", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+		assert.NoError(t, err)
+
+		_, found := p.Find("Custom")
+		assert.False(t, found)
+		assert.Len(t, p.UnknownTags(), 1)
+
+		_, exists := tags.Parsers["#EXT-X-CUSTOM"]
+		assert.False(t, exists)
+	})
+}
@@ -1,30 +1,285 @@
 package go_m3u8
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/globocom/go-m3u8/node"
 	pl "github.com/globocom/go-m3u8/playlist"
 	"github.com/globocom/go-m3u8/tags"
 )
 
-// Converts a Playlist object into an m3u8 formatted string.
-func EncodePlaylist(playlist *pl.Playlist) (string, error) {
-	if playlist == nil || playlist.Head == nil {
-		return "", fmt.Errorf("playlist is empty")
+// Encoder writes m3u8 playlists with its own set of tag encoders, so that custom or proprietary tags can be handled
+// without changing the package-level tags.Encoders map, which is shared by every user of the library.
+//
+// Register all encoders before encoding: Register is not safe for concurrent use, while Encode is.
+type Encoder struct {
+	encoders map[string]tags.PlaylistEncoder
+}
+
+// Returns a new Encoder with a copy of the default tag encoders (tags.Encoders).
+func NewEncoder() *Encoder {
+	return &Encoder{encoders: maps.Clone(tags.Encoders)}
+}
+
+// Registers the encoder for the given HLS element name (e.g. "Custom"), replacing the existing one, and returns the Encoder.
+func (e *Encoder) Register(name string, encoder tags.PlaylistEncoder) *Encoder {
+	e.encoders[name] = encoder
+	return e
+}
+
+// EncodeOption configures how EncodePlaylist writes a playlist.
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+	autoVersion bool
+}
+
+// Writes the Version (#EXT-X-VERSION) tag with the RequiredVersion of the playlist whenever its version is lower,
+// or when it has no Version tag and requires a version greater than 1. The playlist itself is not modified;
+// use Playlist.UpdateVersion to raise its Version tag instead.
+func WithAutoVersion() EncodeOption {
+	return func(o *encodeOptions) {
+		o.autoVersion = true
 	}
+}
 
+// Converts a Playlist object into an m3u8 formatted string, using the Encoder's tag encoders.
+func (e *Encoder) Encode(playlist *pl.Playlist, opts ...EncodeOption) (string, error) {
+	return encodePlaylist(playlist, e.encoders, opts)
+}
+
+// Writes a Playlist object in m3u8 format to w, using the Encoder's tag encoders. See EncodeTo.
+func (e *Encoder) EncodeTo(w io.Writer, playlist *pl.Playlist, opts ...EncodeOption) error {
+	return encodeTo(w, playlist, e.encoders, opts)
+}
+
+// Converts a Playlist object into an m3u8 formatted string, using the tag encoders from tags.Encoders.
+//
+// Nodes parsed in lossless mode (see WithLossless) that were not changed are written back verbatim, and the changed
+// ones keep their original attribute order and the original text of their unchanged attributes.
+func EncodePlaylist(playlist *pl.Playlist, opts ...EncodeOption) (string, error) {
+	return encodePlaylist(playlist, tags.Encoders, opts)
+}
+
+// Writes a Playlist object in m3u8 format to w (e.g. an http.ResponseWriter), using the tag encoders from tags.Encoders,
+// without building the whole manifest in memory first. Writes are buffered, and flushed once the playlist is encoded.
+//
+// On error, part of the manifest may have already been written to w.
+func EncodeTo(w io.Writer, playlist *pl.Playlist, opts ...EncodeOption) error {
+	return encodeTo(w, playlist, tags.Encoders, opts)
+}
+
+func encodePlaylist(playlist *pl.Playlist, encoders map[string]tags.PlaylistEncoder, opts []EncodeOption) (string, error) {
 	var builder strings.Builder
-	current := playlist.Head
-	for current != nil {
-		encoder, exists := tags.Encoders[current.HLSElement.Name]
-		if !exists {
-			return "", fmt.Errorf("unknown tag: %s", current.HLSElement.Name)
+	if err := encodeNodes(&builder, playlist, encoders, opts); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+func encodeTo(w io.Writer, playlist *pl.Playlist, encoders map[string]tags.PlaylistEncoder, opts []EncodeOption) error {
+	buffered := bufio.NewWriter(w)
+	if err := encodeNodes(buffered, playlist, encoders, opts); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+func encodeNodes(w io.Writer, playlist *pl.Playlist, encoders map[string]tags.PlaylistEncoder, opts []EncodeOption) error {
+	if playlist == nil || playlist.Head == nil {
+		return fmt.Errorf("playlist is empty")
+	}
+
+	options := &encodeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var version *node.Node
+	if options.autoVersion {
+		if requiredVersion := playlist.RequiredVersion(); requiredVersion > playlist.Version() {
+			version = &node.Node{
+				HLSElement: &node.HLSElement{
+					Name:  tags.VersionName,
+					Attrs: map[string]string{tags.VersionTag: strconv.Itoa(requiredVersion)},
+				},
+			}
 		}
-		if err := encoder.Encode(current, &builder); err != nil {
-			return "", fmt.Errorf("error encoding tag %s: %w", current.HLSElement.Name, err)
+	}
+	_, hasVersion := playlist.VersionTag()
+
+	var embedded []*node.Node
+	for current := playlist.Head; current != nil; current = current.Next {
+		node := current
+		switch {
+		case version == nil:
+		case current.HLSElement.Name == tags.VersionName:
+			// the raised version keeps the original lines of the Version tag, if any
+			version.HLSElement.Raw = current.HLSElement.Raw
+			node, version = version, nil
+		case !hasVersion && current.HLSElement.Name != tags.M3u8IdentifierName:
+			if err := encodeElement(w, version, encoders); err != nil {
+				return err
+			}
+			version = nil
 		}
-		current = current.Next
+
+		if node.HLSElement.Raw != nil && node.HLSElement.Raw.Embedded {
+			embedded = append(embedded, node)
+			continue
+		}
+		if err := encodeEmbeddedElements(w, embedded, node, encoders); err != nil {
+			return err
+		}
+		embedded = nil
 	}
-	return builder.String(), nil
+	for _, node := range embedded {
+		if err := encodeElement(w, node, encoders); err != nil {
+			return err
+		}
+	}
+	if version != nil && !hasVersion {
+		return encodeElement(w, version, encoders)
+	}
+	return nil
+}
+
+// Encodes the node along with the embedded nodes that precede it (see node.Raw). When none of them was changed,
+// the node's lines are written back verbatim, in their original order. Otherwise, the embedded nodes are encoded
+// before the node.
+func encodeEmbeddedElements(w io.Writer, embedded []*node.Node, owner *node.Node, encoders map[string]tags.PlaylistEncoder) error {
+	raw := owner.HLSElement.Raw
+	unchanged := len(embedded) > 0 && owner.HLSElement.Unchanged() && len(raw.EmbeddedLines) == len(embedded)
+	for _, current := range embedded {
+		unchanged = unchanged && current.HLSElement.Unchanged()
+	}
+	if unchanged {
+		if err := writeLines(w, raw.Lines); err != nil {
+			return err
+		}
+		return writeLines(w, raw.Trailing)
+	}
+
+	for _, current := range embedded {
+		if err := encodeElement(w, current, encoders); err != nil {
+			return err
+		}
+	}
+	return encodeElement(w, owner, encoders)
+}
+
+// Encodes the node, writing it back verbatim when it was parsed in lossless mode and was not changed.
+func encodeElement(w io.Writer, node *node.Node, encoders map[string]tags.PlaylistEncoder) error {
+	raw := node.HLSElement.Raw
+	switch {
+	case node.HLSElement.Unchanged():
+		if err := writeLines(w, ownLines(raw)); err != nil {
+			return err
+		}
+	case raw != nil:
+		if err := writeLines(w, raw.Lines[:raw.Leading]); err != nil {
+			return err
+		}
+		if err := encodeNodeWithRaw(node, w, encoders); err != nil {
+			return err
+		}
+	default:
+		if err := encodeNode(node, w, encoders); err != nil {
+			return err
+		}
+	}
+	if raw != nil {
+		return writeLines(w, raw.Trailing)
+	}
+	return nil
+}
+
+func encodeNode(node *node.Node, w io.Writer, encoders map[string]tags.PlaylistEncoder) error {
+	encoder, exists := encoders[node.HLSElement.Name]
+	if !exists {
+		return fmt.Errorf("unknown tag: %s", node.HLSElement.Name)
+	}
+	if err := encoder.Encode(node, w); err != nil {
+		return fmt.Errorf("error encoding tag %s: %w", node.HLSElement.Name, err)
+	}
+	return nil
+}
+
+// Encodes a changed node that was parsed in lossless mode. Its attributes are written in their original order,
+// followed by the new ones, and the attributes that were not changed keep their original text (e.g. quoting).
+func encodeNodeWithRaw(node *node.Node, w io.Writer, encoders map[string]tags.PlaylistEncoder) error {
+	raw := node.HLSElement.Raw
+	if raw.AttrOrder == nil {
+		return encodeNode(node, w, encoders)
+	}
+
+	var encoded strings.Builder
+	if err := encodeNode(node, &encoded, encoders); err != nil {
+		return err
+	}
+
+	tagLine, rest, _ := strings.Cut(encoded.String(), "\n")
+	tag, attributeList, _ := strings.Cut(tagLine, ":")
+	order, text, ok := splitAttributeList(attributeList)
+	if !ok {
+		_, err := io.WriteString(w, encoded.String())
+		return err
+	}
+
+	attributes := make([]string, 0, len(order))
+	written := make(map[string]bool, len(order))
+	for _, key := range raw.AttrOrder {
+		if _, exists := text[key]; !exists || written[key] {
+			continue
+		}
+		if value, exists := raw.Attrs[key]; exists && value == node.HLSElement.Attrs[key] {
+			attributes = append(attributes, raw.AttrText[key])
+		} else {
+			attributes = append(attributes, text[key])
+		}
+		written[key] = true
+	}
+	for _, key := range order {
+		if !written[key] {
+			attributes = append(attributes, text[key])
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%s:%s\n%s", tag, strings.Join(attributes, ","), rest)
+	return err
+}
+
+// Returns the raw lines of the element, without the lines of the nodes embedded in it.
+func ownLines(raw *node.Raw) []string {
+	return withoutLines(raw.Lines, raw.EmbeddedLines)
+}
+
+// Returns the lines whose index is not in indexes.
+func withoutLines(lines []string, indexes []int) []string {
+	if len(indexes) == 0 {
+		return lines
+	}
+
+	result := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if !slices.Contains(indexes, i) {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+func writeLines(w io.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
 }
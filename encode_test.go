@@ -1,11 +1,17 @@
 package go_m3u8_test
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
 	"testing"
 
 	m3u8 "github.com/globocom/go-m3u8"
 	"github.com/globocom/go-m3u8/internal"
 	pl "github.com/globocom/go-m3u8/playlist"
+	"github.com/globocom/go-m3u8/tags"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -77,6 +83,58 @@ func TestExtInfEncoder(t *testing.T) {
 	assert.NotNil(t, p)
 	assert.Equal(t, "#EXTINF:4.8, no desc\n1.ts\n", p)
 }
+func TestExtInfEncoder_WithByteRange(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "ExtInf",
+			Attrs: map[string]string{
+				"Duration":  "6",
+				"Title":     "",
+				"ByteRange": "1327488",
+			},
+			URI: "main.mp4",
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, "#EXTINF:6\n#EXT-X-BYTERANGE:1327488\nmain.mp4\n", p)
+}
+
+func TestExtInfEncoder_WithGap(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "ExtInf",
+			Attrs: map[string]string{
+				"Duration": "4.8",
+				"Title":    "",
+				"Gap":      "YES",
+			},
+			URI: "segment2.ts",
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, "#EXTINF:4.8\n#EXT-X-GAP\nsegment2.ts\n", p)
+}
+
 func TestStreamInfEncoder(t *testing.T) {
 	node := &internal.Node{
 		HLSElement: &internal.HLSElement{
@@ -110,6 +168,95 @@ func TestStreamInfEncoder(t *testing.T) {
 	assert.Equal(t, expectedPlaylist, p)
 }
 
+func TestStreamInfEncoder_WithContentSteering(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "StreamInf",
+			Attrs: map[string]string{
+				"BANDWIDTH":         "206000",
+				"CODECS":            "mp4a.40.2,avc1.64001F",
+				"PATHWAY-ID":        "CDN-A",
+				"STABLE-VARIANT-ID": "720p",
+			},
+			URI: "playlist.m3u8",
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := `#EXT-X-STREAM-INF:BANDWIDTH=206000,CODECS="mp4a.40.2,avc1.64001F",STABLE-VARIANT-ID="720p",PATHWAY-ID="CDN-A"` + "\n" + "playlist.m3u8\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
+func TestStreamInfEncoder_WithAllAttributes(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "StreamInf",
+			Attrs: map[string]string{
+				"BANDWIDTH":           "6800000",
+				"AVERAGE-BANDWIDTH":   "5500000",
+				"SCORE":               "2.5",
+				"CODECS":              "hvc1.2.4.L150.B0,ec-3",
+				"SUPPLEMENTAL-CODECS": "dvh1.08.07/db4h",
+				"RESOLUTION":          "3840x2160",
+				"FRAME-RATE":          "59.940",
+				"HDCP-LEVEL":          "TYPE-1",
+				"ALLOWED-CPC":         "com.example.drm:SMART-TV/PC",
+				"VIDEO-RANGE":         "HLG",
+				"REQ-VIDEO-LAYOUT":    "CH-MONO",
+				"STABLE-VARIANT-ID":   "2160p-dv",
+				"AUDIO":               "audio",
+				"CLOSED-CAPTIONS":     "NONE",
+				"PROGRAM-ID":          "1",
+				"X-VENDOR-LABEL":      "uhd",
+				"X-VENDOR-WEIGHT":     "10",
+				"X-VENDOR-KEY":        "0x1A2B",
+			},
+			QuotedAttrs: map[string]bool{
+				"X-VENDOR-WEIGHT": false,
+				"X-VENDOR-KEY":    false,
+			},
+			URI: "video/2160p-dv.m3u8",
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := `#EXT-X-STREAM-INF:BANDWIDTH=6800000,AVERAGE-BANDWIDTH=5500000,SCORE=2.5,CODECS="hvc1.2.4.L150.B0,ec-3",` +
+		`SUPPLEMENTAL-CODECS="dvh1.08.07/db4h",RESOLUTION=3840x2160,FRAME-RATE=59.940,HDCP-LEVEL=TYPE-1,ALLOWED-CPC="com.example.drm:SMART-TV/PC",` +
+		`VIDEO-RANGE=HLG,REQ-VIDEO-LAYOUT="CH-MONO",STABLE-VARIANT-ID="2160p-dv",AUDIO="audio",CLOSED-CAPTIONS=NONE,PROGRAM-ID=1,` +
+		`X-VENDOR-KEY=0x1A2B,X-VENDOR-LABEL="uhd",X-VENDOR-WEIGHT=10` + "\n" + "video/2160p-dv.m3u8\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
+func TestStreamInfEncoder_KeepsParsedQuoting(t *testing.T) {
+	playlist := `#EXT-X-STREAM-INF:BANDWIDTH=300000,CLOSED-CAPTIONS="cc",NEW-ATTR=ENUM,X-VENDOR-ID="123",X-VENDOR-MODE=FAST` + "\n" + "playlist.m3u8"
+	p, err := setupPlaylist(playlist)
+	assert.NoError(t, err)
+
+	encoded, err := m3u8.EncodePlaylist(p)
+	assert.NoError(t, err)
+	assert.Equal(t, playlist+"\n", encoded)
+}
+
 func TestCommentEncoder(t *testing.T) {
 	node := &internal.Node{
 		HLSElement: &internal.HLSElement{
@@ -133,6 +280,29 @@ func TestCommentEncoder(t *testing.T) {
 	assert.Equal(t, "## splice_insert(SCTE35-IN matches Auto Return Mode)\n", p)
 }
 
+func TestUnknownTagEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "UnknownTag",
+			Attrs: map[string]string{
+				"UnknownTag": "#EXT-X-CUE-OUT-CONT:ElapsedTime=4.8,Duration=20",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, "#EXT-X-CUE-OUT-CONT:ElapsedTime=4.8,Duration=20\n", p)
+}
+
 func TestDateRangeEncoder(t *testing.T) {
 	node1 := &internal.Node{
 		HLSElement: &internal.HLSElement{
@@ -347,6 +517,32 @@ func TestVariableDefineEncoder(t *testing.T) {
 	assert.Equal(t, expectedPlaylist, p)
 }
 
+func TestStartEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "Start",
+			Attrs: map[string]string{
+				"TIME-OFFSET": "-12.5",
+				"PRECISE":     "YES",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := "#EXT-X-START:TIME-OFFSET=-12.5,PRECISE=YES\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
 func TestKeyEncoder(t *testing.T) {
 	node := &internal.Node{
 		HLSElement: &internal.HLSElement{
@@ -476,6 +672,244 @@ func TestIFramesOnlyEncoder(t *testing.T) {
 	assert.Equal(t, expectedPlaylist, p)
 }
 
+func TestEndlistEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "Endlist",
+			Attrs: map[string]string{
+				"#EXT-X-ENDLIST": "",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := `#EXT-X-ENDLIST` + "\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
+func TestPlaylistTypeEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "PlaylistType",
+			Attrs: map[string]string{
+				"#EXT-X-PLAYLIST-TYPE": "VOD",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := `#EXT-X-PLAYLIST-TYPE:VOD` + "\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
+func TestSkipEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "Skip",
+			Attrs: map[string]string{
+				"SKIPPED-SEGMENTS":            "4",
+				"RECENTLY-REMOVED-DATERANGES": "chapter-0\tchapter-1",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := "#EXT-X-SKIP:SKIPPED-SEGMENTS=4,RECENTLY-REMOVED-DATERANGES=\"chapter-0\tchapter-1\"\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
+func TestSkipEncoder_WithoutSkippedSegments(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "Skip",
+			Attrs: map[string]string{
+				"RECENTLY-REMOVED-DATERANGES": "chapter-0\tchapter-1",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := "#EXT-X-SKIP:RECENTLY-REMOVED-DATERANGES=\"chapter-0\tchapter-1\"\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
+func TestRenditionReportEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "RenditionReport",
+			Attrs: map[string]string{
+				"LAST-PART": "2",
+				"LAST-MSN":  "273",
+				"URI":       "../1M/waitForMSN.php",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := `#EXT-X-RENDITION-REPORT:URI="../1M/waitForMSN.php",LAST-MSN=273,LAST-PART=2` + "\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
+func TestPartInfEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "PartInf",
+			Attrs: map[string]string{
+				"PART-TARGET": "1.004",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := `#EXT-X-PART-INF:PART-TARGET=1.004` + "\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
+func TestServerControlEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "ServerControl",
+			Attrs: map[string]string{
+				"CAN-BLOCK-RELOAD":    "YES",
+				"PART-HOLD-BACK":      "3.012",
+				"HOLD-BACK":           "12",
+				"CAN-SKIP-DATERANGES": "YES",
+				"CAN-SKIP-UNTIL":      "24.0",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := `#EXT-X-SERVER-CONTROL:CAN-SKIP-UNTIL=24.0,CAN-SKIP-DATERANGES=YES,HOLD-BACK=12,PART-HOLD-BACK=3.012,CAN-BLOCK-RELOAD=YES` + "\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
+func TestPartEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "Part",
+			Attrs: map[string]string{
+				"DURATION":    "1.00002",
+				"URI":         "filePart271.1.mp4",
+				"INDEPENDENT": "YES",
+				"BYTERANGE":   "20000@0",
+				"GAP":         "YES",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := `#EXT-X-PART:DURATION=1.00002,URI="filePart271.1.mp4",INDEPENDENT=YES,BYTERANGE="20000@0",GAP=YES` + "\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
+func TestPreloadHintEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "PreloadHint",
+			Attrs: map[string]string{
+				"TYPE":             "PART",
+				"URI":              "filePart272.c.mp4",
+				"BYTERANGE-START":  "20000",
+				"BYTERANGE-LENGTH": "5000",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := `#EXT-X-PRELOAD-HINT:TYPE=PART,URI="filePart272.c.mp4",BYTERANGE-START=20000,BYTERANGE-LENGTH=5000` + "\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
 func TestProgramDateTimeEncoder(t *testing.T) {
 	node := &internal.Node{
 		HLSElement: &internal.HLSElement{
@@ -589,6 +1023,73 @@ func TestSessionKeyEncoder(t *testing.T) {
 	assert.Equal(t, expectedPlaylist, p)
 }
 
+func TestSessionDataEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "SessionData",
+			Attrs: map[string]string{
+				"DATA-ID": "com.globo.chapters",
+				"URI":     "chapters.json",
+				"FORMAT":  "JSON",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := `#EXT-X-SESSION-DATA:DATA-ID="com.globo.chapters",URI="chapters.json",FORMAT=JSON` + "\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+
+	// test session data with VALUE and LANGUAGE
+	node.HLSElement.Attrs = map[string]string{
+		"DATA-ID":  "com.globo.title",
+		"VALUE":    "Jornal Nacional",
+		"LANGUAGE": "pt",
+	}
+
+	expectedPlaylist = `#EXT-X-SESSION-DATA:DATA-ID="com.globo.title",VALUE="Jornal Nacional",LANGUAGE="pt"` + "\n"
+
+	p, err = m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
+func TestContentSteeringEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name: "ContentSteering",
+			Attrs: map[string]string{
+				"SERVER-URI": "https://steering.example.com/manifest.json",
+				"PATHWAY-ID": "CDN-A",
+			},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	expectedPlaylist := `#EXT-X-CONTENT-STEERING:SERVER-URI="https://steering.example.com/manifest.json",PATHWAY-ID="CDN-A"` + "\n"
+
+	p, err := m3u8.EncodePlaylist(playlist)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, expectedPlaylist, p)
+}
+
 func TestEncodeMasterPlaylist(t *testing.T) {
 	node1 := &internal.Node{
 		HLSElement: &internal.HLSElement{
@@ -784,3 +1285,196 @@ func TestEncodeMediaPlaylist(t *testing.T) {
 	assert.NotNil(t, p)
 	assert.Equal(t, expectedPlaylist, p)
 }
+
+func TestEncodePlaylist_Lossless(t *testing.T) {
+	expected, _ := os.ReadFile("./mocks/media/withNonCanonicalFormatting.m3u8")
+
+	// untouched nodes are written back verbatim, along with blank and unrecognized lines
+	file, _ := os.Open("./mocks/media/withNonCanonicalFormatting.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file, m3u8.WithLossless())
+	assert.NoError(t, err)
+
+	p, err := m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), p)
+
+	// changed nodes keep their original attribute order, and new attributes are written after them
+	keys := playlist.EncryptionTags()
+	keys[0].HLSElement.Attrs["URI"] = "https://example.com/keys/key3.bin"
+	keys[1].HLSElement.Attrs["KEYFORMAT"] = "identity"
+	segments := playlist.Segments()
+	segments[2].HLSElement.URI = "channel-audio_1=96000-video=789952-364856603-replaced.ts"
+
+	p, err = m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+
+	expectedPlaylist := strings.NewReplacer(
+		`#EXT-X-KEY:URI="https://example.com/keys/key1.bin",METHOD=AES-128`,
+		`#EXT-X-KEY:URI="https://example.com/keys/key3.bin",METHOD=AES-128`,
+		`#EXT-X-KEY:IV=0x0123456789abcdef0123456789abcdf0,URI="https://example.com/keys/key2.bin",METHOD=AES-128`,
+		`#EXT-X-KEY:IV=0x0123456789abcdef0123456789abcdf0,URI="https://example.com/keys/key2.bin",METHOD=AES-128,KEYFORMAT="identity"`,
+		"364856603.ts",
+		"364856603-replaced.ts",
+	).Replace(string(expected))
+	assert.Equal(t, expectedPlaylist, p)
+
+	// tags between #EXTINF and its URI are encoded back in their original place
+	manifest := "#EXTM3U\n#EXTINF:4.8, no desc\n#EXT-X-PROGRAM-DATE-TIME:2025-06-30T19:28:00.100000Z\n#EXT-X-CUE-OUT-CONT:ElapsedTime=4.8,Duration=20\n1.ts\n"
+	playlist, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)), m3u8.WithLossless())
+	assert.NoError(t, err)
+
+	p, err = m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest, p)
+
+	// once the segment or an embedded tag changes, the embedded tags are encoded before the segment
+	playlist.Segments()[0].HLSElement.URI = "2.ts"
+	p, err = m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Equal(t, "#EXTM3U\n#EXT-X-PROGRAM-DATE-TIME:2025-06-30T19:28:00.100000Z\n#EXT-X-CUE-OUT-CONT:ElapsedTime=4.8,Duration=20\n#EXTINF:4.8, no desc\n2.ts\n", p)
+
+	playlist.Segments()[0].HLSElement.URI = "1.ts"
+	playlist.UnknownTags()[0].HLSElement.Attrs["UnknownTag"] = "#EXT-X-CUE-OUT-CONT:ElapsedTime=4.8,Duration=30"
+	p, err = m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Equal(t, "#EXTM3U\n#EXT-X-PROGRAM-DATE-TIME:2025-06-30T19:28:00.100000Z\n#EXT-X-CUE-OUT-CONT:ElapsedTime=4.8,Duration=30\n#EXTINF:4.8, no desc\n1.ts\n", p)
+
+	// without lossless mode, nodes are encoded from their attributes
+	file, _ = os.Open("./mocks/media/withNonCanonicalFormatting.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	p, err = m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+	assert.NotEqual(t, string(expected), p)
+	assert.Contains(t, p, `#EXT-X-KEY:METHOD=AES-128,URI="https://example.com/keys/key1.bin",IV=0x0123456789abcdef0123456789abcdef,KEYFORMAT="identity"`)
+}
+
+type customTagEncoder struct {
+	tag string
+}
+
+func (e customTagEncoder) Encode(node *internal.Node, w io.Writer) error {
+	_, err := io.WriteString(w, e.tag+":"+node.HLSElement.Attrs["VALUE"]+"\n")
+	return err
+}
+
+func TestEncoder(t *testing.T) {
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name:  "Custom",
+			Attrs: map[string]string{"VALUE": "42"},
+		},
+	}
+	playlist := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+
+	t.Run("registered encoder", func(t *testing.T) {
+		t.Parallel()
+
+		encoder := m3u8.NewEncoder().Register("Custom", customTagEncoder{tag: "#EXT-X-CUSTOM"})
+		p, err := encoder.Encode(playlist)
+		assert.NoError(t, err)
+		assert.Equal(t, "#EXT-X-CUSTOM:42\n", p)
+	})
+
+	t.Run("same element with another encoder", func(t *testing.T) {
+		t.Parallel()
+
+		encoder := m3u8.NewEncoder().Register("Custom", customTagEncoder{tag: "#EXT-X-OTHER"})
+		p, err := encoder.Encode(playlist)
+		assert.NoError(t, err)
+		assert.Equal(t, "#EXT-X-OTHER:42\n", p)
+	})
+
+	t.Run("default encoders", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := m3u8.EncodePlaylist(playlist)
+		assert.Error(t, err)
+
+		_, exists := tags.Encoders["Custom"]
+		assert.False(t, exists)
+	})
+}
+
+type failingWriter struct{}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+func TestEncodeTo(t *testing.T) {
+	file, _ := os.Open("./mocks/media/withNonCanonicalFormatting.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file, m3u8.WithLossless())
+	assert.NoError(t, err)
+
+	expected, err := m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+
+	var buffer bytes.Buffer
+	err = m3u8.EncodeTo(&buffer, playlist)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, buffer.String())
+
+	// write errors are returned
+	err = m3u8.EncodeTo(failingWriter{}, playlist)
+	assert.EqualError(t, err, "connection closed")
+
+	// nothing is written for an empty playlist
+	buffer.Reset()
+	err = m3u8.EncodeTo(&buffer, pl.NewPlaylist())
+	assert.EqualError(t, err, "playlist is empty")
+	assert.Empty(t, buffer.String())
+
+	// the Encoder writes with its own encoders
+	node := &internal.Node{
+		HLSElement: &internal.HLSElement{
+			Name:  "Custom",
+			Attrs: map[string]string{"VALUE": "42"},
+		},
+	}
+	custom := &pl.Playlist{
+		DoublyLinkedList: &internal.DoublyLinkedList{
+			Head: node,
+			Tail: node,
+		},
+	}
+	buffer.Reset()
+	err = m3u8.NewEncoder().Register("Custom", customTagEncoder{tag: "#EXT-X-CUSTOM"}).EncodeTo(&buffer, custom)
+	assert.NoError(t, err)
+	assert.Equal(t, "#EXT-X-CUSTOM:42\n", buffer.String())
+}
+
+func TestEncodePlaylist_AutoVersion(t *testing.T) {
+	manifest := "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\nsegment1.mp4\n"
+	playlist, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)), m3u8.WithLossless())
+	assert.NoError(t, err)
+
+	// adding an init section requires version 6
+	playlist.InsertBefore(playlist.Segments()[0], playlist.NewNode(tags.MapName, "", map[string]string{"URI": "init.mp4"}, nil))
+
+	p, err := m3u8.EncodePlaylist(playlist, m3u8.WithAutoVersion())
+	assert.NoError(t, err)
+	assert.Equal(t, "#EXTM3U\n#EXT-X-VERSION:6\n#EXT-X-TARGETDURATION:6\n#EXT-X-MAP:URI=\"init.mp4\"\n#EXTINF:6,\nsegment1.mp4\n", p)
+	assert.Equal(t, "3", playlist.VersionValue())
+
+	// without the option, the version tag is kept as it is
+	p, err = m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Contains(t, p, "#EXT-X-VERSION:3\n")
+
+	// the version tag is added when missing
+	manifest = "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:5.5,\nsegment1.ts\n"
+	playlist, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+
+	var buffer bytes.Buffer
+	err = m3u8.EncodeTo(&buffer, playlist, m3u8.WithAutoVersion())
+	assert.NoError(t, err)
+	assert.Equal(t, "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXTINF:5.5\nsegment1.ts\n", buffer.String())
+}
@@ -0,0 +1,58 @@
+package go_m3u8
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError describes a manifest line that could not be parsed. Use errors.As to retrieve it from the errors
+// returned by ParsePlaylist, Decoder.Parse and StreamPlaylist.
+type ParseError struct {
+	Line int    // 1-based line number in the manifest
+	Tag  string // Tag of the line (e.g. "#EXT-X-KEY"), empty for URI lines
+	Raw  string // Line exactly as it was read
+	Err  error  // Error returned by the parser
+}
+
+func newParseError(lineNumber int, raw, tag string, err error) *ParseError {
+	return &ParseError{
+		Line: lineNumber,
+		Tag:  tag,
+		Raw:  raw,
+		Err:  err,
+	}
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.message())
+}
+
+// Returns the error message without the position of the line.
+func (e *ParseError) message() string {
+	switch {
+	case e.Tag != "":
+		return fmt.Sprintf("error parsing tag %s: %v", e.Tag, e.Err)
+	case e.Raw != "":
+		return fmt.Sprintf("error handling multi-line HLS element %q: %v", strings.TrimSpace(e.Raw), e.Err)
+	default:
+		return fmt.Sprintf("failed to parse playlist: %v", e.Err)
+	}
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// DiagnosticsError holds the problems found in a manifest parsed in lenient mode (see WithLenient), in manifest order.
+// ParsePlaylist and Decoder.Parse return it along with the parsed Playlist, which is usable despite the error.
+type DiagnosticsError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *DiagnosticsError) Error() string {
+	first := e.Diagnostics[0]
+	if len(e.Diagnostics) == 1 {
+		return fmt.Sprintf("line %d: %s", first.Line, first.Message)
+	}
+	return fmt.Sprintf("line %d: %s (and %d more problems)", first.Line, first.Message, len(e.Diagnostics)-1)
+}
@@ -0,0 +1,63 @@
+package go_m3u8_test
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseError(t *testing.T) {
+	manifest := `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+
+#EXTINF:6.0,
+segment1.ts
+  #EXT-X-KEY:METHOD=AES-128
+#EXTINF:6.0,
+segment2.ts`
+
+	_, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+
+	var parseErr *m3u8.ParseError
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 7, parseErr.Line)
+	assert.Equal(t, "#EXT-X-KEY", parseErr.Tag)
+	assert.Equal(t, "  #EXT-X-KEY:METHOD=AES-128", parseErr.Raw)
+	assert.EqualError(t, err, "line 7: error parsing tag #EXT-X-KEY: "+parseErr.Err.Error())
+
+	// the parser error is kept in the chain
+	manifest = `#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXTINF:six,
+segment1.ts`
+
+	_, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 3, parseErr.Line)
+	assert.Equal(t, "#EXTINF", parseErr.Tag)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+
+	// lines that are too long to be read
+	manifest = "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:6.0," + strings.Repeat("a", 70*1024) + "\nsegment1.ts"
+
+	_, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 3, parseErr.Line)
+	assert.Empty(t, parseErr.Tag)
+	assert.Empty(t, parseErr.Raw)
+
+	// errors yielded by StreamPlaylist are ParseErrors too
+	for _, err := range m3u8.StreamPlaylist(io.NopCloser(strings.NewReader("#EXTM3U\n#EXT-X-KEY:METHOD=AES-128"))) {
+		if err != nil {
+			assert.True(t, errors.As(err, &parseErr))
+			assert.Equal(t, 2, parseErr.Line)
+			assert.Equal(t, "#EXT-X-KEY", parseErr.Tag)
+		}
+	}
+}
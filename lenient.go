@@ -0,0 +1,124 @@
+package go_m3u8
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/globocom/go-m3u8/node"
+	pl "github.com/globocom/go-m3u8/playlist"
+	"github.com/globocom/go-m3u8/tags"
+)
+
+// Severity of a Diagnostic.
+type Severity string
+
+const (
+	// The line is invalid, and was skipped or kept according to the InvalidElementPolicy.
+	SeverityError Severity = "error"
+	// The line was parsed, but it is likely a mistake in the manifest.
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic codes.
+const (
+	// A tag could not be parsed (e.g. a required attribute is missing).
+	DiagnosticInvalidTag = "invalid-tag"
+	// The URI line of a multi-line element could not be handled (e.g. an invalid byte range for the segment).
+	DiagnosticInvalidURI = "invalid-uri"
+	// A URI line does not follow an #EXTINF or #EXT-X-STREAM-INF tag, so it does not belong to any element.
+	DiagnosticUnexpectedURI = "unexpected-uri"
+	// A Cue Out (#EXT-X-CUE-OUT) tag has no duration, so it was parsed with duration 0.
+	DiagnosticMissingCueOutDuration = "missing-cue-out-duration"
+	// A line could not be read (e.g. it is too long), so the rest of the manifest was not parsed.
+	DiagnosticUnreadableLine = "unreadable-line"
+)
+
+// Diagnostic describes a problem found in a manifest parsed in lenient mode (see WithLenient).
+type Diagnostic struct {
+	Severity Severity
+	Line     int // 1-based line number in the manifest
+	Code     string
+	Message  string
+}
+
+// InvalidElementPolicy defines what happens to the invalid lines of a manifest parsed in lenient mode.
+type InvalidElementPolicy int
+
+const (
+	// Invalid lines are dropped, as if they were not in the manifest.
+	SkipInvalidElements InvalidElementPolicy = iota
+	// Invalid tags are kept as UnknownTag nodes, so that they are encoded back as they were read.
+	// Segments and Variant Streams whose URI line is invalid are kept as parsed.
+	KeepInvalidElements
+)
+
+type lenientOptions struct {
+	policy InvalidElementPolicy
+}
+
+// Parses the whole manifest even when some of its lines are invalid, instead of failing on the first one.
+// Invalid lines are skipped or kept according to the given policy. The problems found are returned, in manifest order,
+// as a DiagnosticsError along with the parsed Playlist (or yielded last by StreamPlaylist).
+func WithLenient(policy InvalidElementPolicy) ParseOption {
+	return func(o *parseOptions) {
+		o.lenient = &lenientOptions{policy: policy}
+	}
+}
+
+// Reports the error in lenient mode, applying the invalid element policy to the nodes inserted since tail.
+// Returns true if the line was kept, or the error itself when not in lenient mode.
+func (d *lineDecoder) recover(err *ParseError, tail *node.Node) (bool, error) {
+	if d.options.lenient == nil {
+		return false, err
+	}
+
+	code := DiagnosticInvalidTag
+	if err.Tag == "" {
+		code = DiagnosticInvalidURI
+	}
+	d.diagnose(SeverityError, err.Line, code, err.message())
+
+	if d.options.lenient.policy == SkipInvalidElements {
+		for d.playlist.Tail != tail {
+			removed := d.playlist.Tail
+			d.playlist.DoublyLinkedList.Remove(removed)
+
+			// the #EXTINF tag of a dropped segment was already added to the playlist counters
+			if removed.HLSElement.Name == tags.ExtInfName {
+				duration, _ := strconv.ParseFloat(removed.HLSElement.Attrs["Duration"], 64)
+				d.playlist.DVR = pl.RoundFloat(d.playlist.DVR-duration, 4)
+				d.playlist.SegmentsCounter--
+			}
+		}
+		return false, nil
+	}
+
+	if err.Tag != "" && d.playlist.Tail == tail {
+		if parser, exists := d.parsers[tags.UnknownTagTag]; exists {
+			if err := parser.Parse(strings.TrimSpace(err.Raw), d.playlist); err != nil {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+func (d *lineDecoder) diagnose(severity Severity, line int, code, message string) {
+	if d.options.lenient == nil {
+		return
+	}
+	d.diagnostics = append(d.diagnostics, Diagnostic{
+		Severity: severity,
+		Line:     line,
+		Code:     code,
+		Message:  message,
+	})
+}
+
+// Returns the DiagnosticsError with the problems found so far, or nil if there are none.
+func (d *lineDecoder) diagnosticsError() error {
+	if len(d.diagnostics) == 0 {
+		return nil
+	}
+	return &DiagnosticsError{Diagnostics: d.diagnostics}
+}
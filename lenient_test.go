@@ -0,0 +1,134 @@
+package go_m3u8_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	"github.com/globocom/go-m3u8/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePlaylist_Lenient(t *testing.T) {
+	manifest := `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXT-X-CUE-OUT
+#EXTINF:6.0,
+segment1.ts
+#EXT-X-KEY:METHOD=AES-128
+#EXTINF:six,
+segment2.ts
+#EXTINF:6.0,
+segment3.ts`
+
+	t.Run("invalid elements are skipped", func(t *testing.T) {
+		p, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)), m3u8.WithLenient(m3u8.SkipInvalidElements))
+		var diagnosticsErr *m3u8.DiagnosticsError
+		assert.True(t, errors.As(err, &diagnosticsErr))
+		diagnostics := diagnosticsErr.Diagnostics
+
+		assert.Len(t, p.Segments(), 2)
+		assert.Equal(t, "segment1.ts", p.Segments()[0].HLSElement.URI)
+		assert.Equal(t, "segment3.ts", p.Segments()[1].HLSElement.URI)
+		assert.Empty(t, p.UnknownTags())
+		assert.Len(t, p.FindAll(tags.EventCueOutName), 1)
+
+		assert.Len(t, diagnostics, 4)
+		assert.Equal(t, m3u8.Diagnostic{
+			Severity: m3u8.SeverityWarning,
+			Line:     4,
+			Code:     m3u8.DiagnosticMissingCueOutDuration,
+			Message:  "tag #EXT-X-CUE-OUT has no duration, 0 is assumed",
+		}, diagnostics[0])
+		assert.Equal(t, 7, diagnostics[1].Line)
+		assert.Equal(t, m3u8.DiagnosticInvalidTag, diagnostics[1].Code)
+		assert.Equal(t, 8, diagnostics[2].Line)
+		assert.Equal(t, m3u8.DiagnosticInvalidTag, diagnostics[2].Code)
+		assert.Equal(t, m3u8.Diagnostic{
+			Severity: m3u8.SeverityWarning,
+			Line:     9,
+			Code:     m3u8.DiagnosticUnexpectedURI,
+			Message:  `URI "segment2.ts" does not follow an #EXTINF or #EXT-X-STREAM-INF tag`,
+		}, diagnostics[3])
+	})
+
+	t.Run("invalid elements are kept", func(t *testing.T) {
+		p, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)), m3u8.WithLenient(m3u8.KeepInvalidElements))
+		var diagnosticsErr *m3u8.DiagnosticsError
+		assert.True(t, errors.As(err, &diagnosticsErr))
+		diagnostics := diagnosticsErr.Diagnostics
+		assert.Len(t, diagnostics, 4)
+
+		unknownTags := p.UnknownTags()
+		assert.Len(t, unknownTags, 2)
+		assert.Equal(t, "#EXT-X-KEY:METHOD=AES-128", unknownTags[0].HLSElement.Attrs[tags.UnknownTagName])
+		assert.Equal(t, "#EXTINF:six,", unknownTags[1].HLSElement.Attrs[tags.UnknownTagName])
+
+		encoded, err := m3u8.EncodePlaylist(p)
+		assert.NoError(t, err)
+		assert.Contains(t, encoded, "#EXT-X-KEY:METHOD=AES-128\n#EXTINF:six,\n")
+	})
+
+	t.Run("invalid byte ranges are reported on the URI line", func(t *testing.T) {
+		manifest := `#EXTM3U
+#EXT-X-VERSION:4
+#EXT-X-TARGETDURATION:6
+#EXTINF:6.0,
+#EXT-X-BYTERANGE:1000
+segment.ts
+#EXTINF:6.0,
+#EXT-X-BYTERANGE:1000@0
+segment.ts`
+
+		p, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)), m3u8.WithLenient(m3u8.SkipInvalidElements))
+		var diagnosticsErr *m3u8.DiagnosticsError
+		assert.True(t, errors.As(err, &diagnosticsErr))
+		diagnostics := diagnosticsErr.Diagnostics
+		assert.Len(t, p.Segments(), 1)
+		assert.Equal(t, "1000@0", p.Segments()[0].HLSElement.Attrs["ByteRange"])
+		// the dropped segment is not counted
+		assert.Equal(t, "0", p.Segments()[0].HLSElement.Details["MediaSequence"])
+		assert.Equal(t, 1, p.SegmentsCounter)
+		assert.Equal(t, 6.0, p.DVR)
+
+		assert.Len(t, diagnostics, 1)
+		assert.Equal(t, 6, diagnostics[0].Line)
+		assert.Equal(t, m3u8.DiagnosticInvalidURI, diagnostics[0].Code)
+	})
+
+	t.Run("diagnostics are reported as an error", func(t *testing.T) {
+		_, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)), m3u8.WithLenient(m3u8.SkipInvalidElements))
+		assert.EqualError(t, err, "line 4: tag #EXT-X-CUE-OUT has no duration, 0 is assumed (and 3 more problems)")
+
+		// valid manifests have no diagnostics
+		_, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader("#EXTM3U\n#EXT-X-TARGETDURATION:6\n")), m3u8.WithLenient(m3u8.SkipInvalidElements))
+		assert.NoError(t, err)
+	})
+
+	t.Run("streamed diagnostics are yielded last", func(t *testing.T) {
+		count := 0
+		var streamErr error
+		for element, err := range m3u8.StreamPlaylist(io.NopCloser(strings.NewReader(manifest)), m3u8.WithLenient(m3u8.SkipInvalidElements)) {
+			if err != nil {
+				assert.Nil(t, element)
+				streamErr = err
+				continue
+			}
+			assert.NoError(t, streamErr)
+			count++
+		}
+
+		var diagnosticsErr *m3u8.DiagnosticsError
+		assert.True(t, errors.As(streamErr, &diagnosticsErr))
+		assert.Len(t, diagnosticsErr.Diagnostics, 4)
+		assert.Equal(t, 6, count)
+	})
+
+	t.Run("strict mode fails on the first invalid line", func(t *testing.T) {
+		_, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+		assert.ErrorContains(t, err, "line 7: ")
+	})
+}
@@ -0,0 +1,314 @@
+package node
+
+import (
+	"maps"
+	"slices"
+)
+
+// A HLS Playlist is a doubly-linked list of of Node objects.
+// Each Node represents a HLSElement of the Playlist, amounting to one or more lines of the m3u8 file.
+// For example, a Media Segment Node will be comprised of two lines: the #EXTINF tag + the segment URI below it.
+// Alternatively, a Media Sequence Node is only one line long: the #EXT-X-MEDIA-SEQUENCE tag.
+type DoublyLinkedList struct {
+	Head, Tail *Node
+}
+
+// The Node data type holds the following attributes:
+//   - HLSElement: Pointer to HLSElement it represents on the list.
+//   - Prev, Next: Pointers to previous or next Node in the list.
+type Node struct {
+	HLSElement *HLSElement
+	Prev, Next *Node
+}
+
+// The HLSElement data type holds the following attributes:
+//   - Name: The name of the Element (e.g. tag name).
+//   - URI: The Uniform Resource Identifier of the Element (if applicable).
+//   - Attrs: In-manifest Element attributes, in key-value format.
+//   - Details: Not-in-manifest Element attributes, in key-value format.
+//   - QuotedAttrs: Whether each attribute value was a quoted-string in the manifest, for the Elements whose attributes
+//     have no fixed quoting (e.g. StreamInf, whose attribute-list may hold client-defined X- attributes).
+//   - Raw: The manifest lines the Element was parsed from (only when parsed in lossless mode).
+type HLSElement struct {
+	Name        string
+	URI         string
+	Attrs       map[string]string
+	Details     map[string]string
+	QuotedAttrs map[string]bool
+	Raw         *Raw
+}
+
+// The Raw data type holds the manifest lines an HLSElement was parsed from, exactly as they were read:
+//   - Lines: The original lines, starting with the blank or unrecognized lines that precede the Element's own lines.
+//   - Leading: Number of lines in Lines that precede the Element's own lines.
+//   - Trailing: Blank or unrecognized lines that follow the last Element of the manifest.
+//   - URI, Attrs: Snapshot of the Element's URI and attributes right after parsing, used to detect changes.
+//   - AttrOrder: Attribute keys in the order they appear in the tag line.
+//   - AttrText: Each attribute exactly as written in the tag line (e.g. KEY="value"), keyed by attribute key.
+//   - Embedded: True if the Element was parsed between the lines of the multi-line Element that follows it
+//     (e.g. a tag between #EXTINF and its URI), whose Lines also hold the Element's line.
+//   - EmbeddedLines: Indexes in Lines of the lines of the Embedded Elements that precede this one in the list.
+type Raw struct {
+	Lines         []string
+	Leading       int
+	Trailing      []string
+	URI           string
+	Attrs         map[string]string
+	AttrOrder     []string
+	AttrText      map[string]string
+	Embedded      bool
+	EmbeddedLines []int
+}
+
+// Returns true if the Element has its raw manifest lines and was not changed since it was parsed,
+// so it can be encoded back verbatim.
+func (e *HLSElement) Unchanged() bool {
+	return e.Raw != nil && e.URI == e.Raw.URI && maps.Equal(e.Attrs, e.Raw.Attrs)
+}
+
+// Creates a new Node with the given HLSElement attributes.
+func (l *DoublyLinkedList) NewNode(name, uri string, attrs, details map[string]string) *Node {
+	element := &HLSElement{
+		Name:    name,
+		URI:     uri,
+		Attrs:   attrs,
+		Details: details,
+	}
+	return &Node{HLSElement: element}
+}
+
+// Adds a new node to the end of the doubly linked list
+func (l *DoublyLinkedList) Insert(node *Node) {
+	if l.Head == nil {
+		l.Head = node
+		l.Tail = node
+	} else {
+		node.Prev = l.Tail
+		l.Tail.Next = node
+		l.Tail = node
+	}
+}
+
+// Inserts newNode after node in the doubly linked list
+//
+//	node ---> newNode ---> node.Next
+func (l *DoublyLinkedList) InsertAfter(node, newNode *Node) {
+	if node == nil {
+		return
+	}
+
+	newNode.Prev = node
+	newNode.Next = node.Next
+	node.Next = newNode
+
+	if newNode.Next != nil {
+		newNode.Next.Prev = newNode
+	} else {
+		l.Tail = newNode
+	}
+}
+
+// Inserts newNode before node in the doubly linked list
+//
+//	node.Prev ---> newNode ---> node
+func (l *DoublyLinkedList) InsertBefore(node, newNode *Node) {
+	if node == nil {
+		return
+	}
+
+	newNode.Next = node
+	newNode.Prev = node.Prev
+	node.Prev = newNode
+
+	if newNode.Prev != nil {
+		newNode.Prev.Next = newNode
+	} else {
+		l.Head = newNode
+	}
+}
+
+// Inserts newNode between node1 and node2 in the doubly linked list
+//
+//	node1 ---> newNode ---> node2
+func (l *DoublyLinkedList) InsertBetween(node1, node2, newNode *Node) {
+	if node1 == nil || node2 == nil {
+		return
+	}
+
+	if node1.Next != node2 {
+		return
+	}
+
+	newNode.Prev = node1
+	newNode.Next = node2
+	node1.Next = newNode
+	node2.Prev = newNode
+}
+
+// Searches for a node with the specified element name in the doubly linked list
+func (l *DoublyLinkedList) Find(elementName string) (*Node, bool) {
+	current := l.Head
+	for current != nil {
+		if current.HLSElement.Name == elementName {
+			return current, true
+		}
+		current = current.Next
+	}
+
+	return nil, false
+}
+
+// Searches for all nodes with the specified element name in the doubly linked list
+func (l *DoublyLinkedList) FindAll(elementName string) []*Node {
+	current := l.Head
+	result := make([]*Node, 0)
+	for current != nil {
+		if current.HLSElement.Name == elementName {
+			result = append(result, current)
+		}
+		current = current.Next
+	}
+	return result
+}
+
+// Removes node from the doubly linked list.
+// Nothing is removed if node is not linked to the list (e.g. it was already removed).
+//
+//	node.Prev ---> node.Next
+func (l *DoublyLinkedList) Remove(node *Node) {
+	if !l.linked(node) {
+		return
+	}
+
+	if node.Prev != nil {
+		node.Prev.Next = node.Next
+	} else {
+		l.Head = node.Next
+	}
+
+	if node.Next != nil {
+		node.Next.Prev = node.Prev
+	} else {
+		l.Tail = node.Prev
+	}
+
+	node.Prev, node.Next = nil, nil
+}
+
+// Replaces oldNode with newNode in the doubly linked list.
+// Nothing is replaced if oldNode is not linked to the list.
+//
+//	oldNode.Prev ---> newNode ---> oldNode.Next
+func (l *DoublyLinkedList) Replace(oldNode, newNode *Node) {
+	if !l.linked(oldNode) || newNode == nil {
+		return
+	}
+
+	l.InsertAfter(oldNode, newNode)
+	l.Remove(oldNode)
+}
+
+// Removes the nodes from node1 to node2 (both included) from the doubly linked list.
+// Nothing is removed if node1 or node2 is not linked to the list, or if node2 is not node1 or one of the nodes after it.
+//
+//	node1.Prev ---> node2.Next
+func (l *DoublyLinkedList) RemoveRange(node1, node2 *Node) {
+	if !l.linked(node1) || !l.linked(node2) {
+		return
+	}
+
+	current := node1
+	for current != nil && current != node2 {
+		current = current.Next
+	}
+	if current == nil {
+		return
+	}
+
+	if node1.Prev != nil {
+		node1.Prev.Next = node2.Next
+	} else {
+		l.Head = node2.Next
+	}
+
+	if node2.Next != nil {
+		node2.Next.Prev = node1.Prev
+	} else {
+		l.Tail = node1.Prev
+	}
+
+	node1.Prev, node2.Next = nil, nil
+}
+
+// Moves all nodes of otherList after node in the doubly linked list, leaving otherList empty.
+// Nothing is moved if node is not linked to the list.
+//
+//	node ---> otherList.Head ... otherList.Tail ---> node.Next
+func (l *DoublyLinkedList) SpliceAfter(node *Node, otherList *DoublyLinkedList) {
+	if !l.linked(node) || otherList == nil || otherList.Head == nil || otherList == l {
+		return
+	}
+
+	otherList.Tail.Next = node.Next
+	if node.Next != nil {
+		node.Next.Prev = otherList.Tail
+	} else {
+		l.Tail = otherList.Tail
+	}
+
+	node.Next = otherList.Head
+	otherList.Head.Prev = node
+
+	otherList.Head, otherList.Tail = nil, nil
+}
+
+// Returns true if node is linked to the list: a node without a previous (next) node must be the list's Head (Tail).
+// A detached node (e.g. already removed) or the first or last node of another list is not linked. A node in the middle
+// of another list is not detected, as that would require walking the list: callers must pass nodes of this list.
+func (l *DoublyLinkedList) linked(node *Node) bool {
+	return node != nil && (node.Prev != nil || l.Head == node) && (node.Next != nil || l.Tail == node)
+}
+
+// Returns a deep copy of the HLSElement, with its own attribute maps and raw manifest lines.
+func (e *HLSElement) Clone() *HLSElement {
+	if e == nil {
+		return nil
+	}
+
+	return &HLSElement{
+		Name:        e.Name,
+		URI:         e.URI,
+		Attrs:       maps.Clone(e.Attrs),
+		Details:     maps.Clone(e.Details),
+		QuotedAttrs: maps.Clone(e.QuotedAttrs),
+		Raw:         e.Raw.Clone(),
+	}
+}
+
+// Returns a deep copy of the Raw manifest lines.
+func (r *Raw) Clone() *Raw {
+	if r == nil {
+		return nil
+	}
+
+	return &Raw{
+		Lines:         slices.Clone(r.Lines),
+		Leading:       r.Leading,
+		Trailing:      slices.Clone(r.Trailing),
+		URI:           r.URI,
+		Attrs:         maps.Clone(r.Attrs),
+		AttrOrder:     slices.Clone(r.AttrOrder),
+		AttrText:      maps.Clone(r.AttrText),
+		Embedded:      r.Embedded,
+		EmbeddedLines: slices.Clone(r.EmbeddedLines),
+	}
+}
+
+// Returns a deep copy of the doubly linked list, whose nodes hold copies of the original HLSElements.
+func (l *DoublyLinkedList) Clone() *DoublyLinkedList {
+	result := new(DoublyLinkedList)
+	for current := l.Head; current != nil; current = current.Next {
+		result.Insert(&Node{HLSElement: current.HLSElement.Clone()})
+	}
+	return result
+}
@@ -0,0 +1,396 @@
+package node_test
+
+import (
+	"testing"
+
+	"github.com/globocom/go-m3u8/node"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoublyLinkedListInsert(t *testing.T) {
+	list := node.DoublyLinkedList{}
+
+	firstNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "Version",
+			Attrs: map[string]string{
+				"#EXT-X-VERSION": "3",
+			},
+		},
+	}
+
+	list.Insert(firstNode)
+
+	assert.Equal(t, firstNode, list.Head)
+	assert.Equal(t, firstNode, list.Tail)
+	assert.Nil(t, firstNode.Prev)
+	assert.Nil(t, firstNode.Next)
+
+	secondNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "MediaSequence",
+			Attrs: map[string]string{
+				"#EXT-X-MEDIA-SEQUENCE": "360948012",
+			},
+		},
+	}
+
+	list.Insert(secondNode)
+
+	assert.Equal(t, firstNode, list.Head)
+	assert.Equal(t, secondNode, list.Tail)
+	assert.Equal(t, firstNode, secondNode.Prev)
+	assert.Equal(t, secondNode, firstNode.Next)
+
+}
+
+func TestDoublyLinkedListInsertAfter(t *testing.T) {
+	list := node.DoublyLinkedList{}
+
+	firstNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "M3u8Identifier",
+		},
+	}
+
+	secondNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "Comment",
+			Attrs: map[string]string{
+				"Comment": "## Created with Unified Streaming Platform (version=1.11.23-28141)",
+			},
+		},
+	}
+
+	list.Insert(firstNode)
+	list.InsertAfter(firstNode, secondNode)
+
+	assert.Equal(t, firstNode, list.Head)
+	assert.Equal(t, secondNode, list.Tail)
+	assert.Equal(t, firstNode, secondNode.Prev)
+	assert.Equal(t, secondNode, firstNode.Next)
+
+	newNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "Version",
+			Attrs: map[string]string{
+				"#EXT-X-VERSION": "3",
+			},
+		},
+	}
+
+	list.InsertAfter(firstNode, newNode)
+
+	assert.Equal(t, firstNode, list.Head)
+	assert.Equal(t, secondNode, list.Tail)
+	assert.Equal(t, newNode, firstNode.Next)
+	assert.Equal(t, newNode, secondNode.Prev)
+	assert.Equal(t, firstNode, newNode.Prev)
+	assert.Equal(t, secondNode, newNode.Next)
+}
+
+func TestDoublyLinkedListInsertBefore(t *testing.T) {
+	list := node.DoublyLinkedList{}
+
+	firstNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "M3u8Identifier",
+		},
+	}
+
+	secondNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "Comment",
+			Attrs: map[string]string{
+				"Comment": "## Created with Unified Streaming Platform (version=1.11.23-28141)",
+			},
+		},
+	}
+
+	list.Insert(secondNode)
+	list.InsertBefore(secondNode, firstNode)
+
+	assert.Equal(t, firstNode, list.Head)
+	assert.Equal(t, secondNode, list.Tail)
+	assert.Equal(t, firstNode, secondNode.Prev)
+	assert.Equal(t, secondNode, firstNode.Next)
+
+	newNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "Version",
+			Attrs: map[string]string{
+				"#EXT-X-VERSION": "3",
+			},
+		},
+	}
+
+	list.InsertBefore(secondNode, newNode)
+
+	assert.Equal(t, firstNode, list.Head)
+	assert.Equal(t, secondNode, list.Tail)
+	assert.Equal(t, newNode, firstNode.Next)
+	assert.Equal(t, newNode, secondNode.Prev)
+	assert.Equal(t, firstNode, newNode.Prev)
+	assert.Equal(t, secondNode, newNode.Next)
+}
+
+func TestDoublyLinkedListInsertBetween(t *testing.T) {
+	list := node.DoublyLinkedList{}
+
+	firstNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "M3u8Identifier",
+		},
+	}
+	secondNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "Version",
+			Attrs: map[string]string{
+				"#EXT-X-VERSION": "3",
+			},
+		},
+	}
+	thirdNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "Comment",
+			Attrs: map[string]string{
+				"Comment": "## Created with Unified Streaming Platform (version=1.11.23-28141)",
+			},
+		},
+	}
+
+	list.Insert(firstNode)
+	list.Insert(thirdNode)
+
+	assert.Equal(t, firstNode, list.Head)
+	assert.Equal(t, thirdNode, list.Tail)
+
+	list.InsertBetween(firstNode, thirdNode, secondNode)
+
+	assert.Equal(t, firstNode, list.Head)
+	assert.Equal(t, thirdNode, list.Tail)
+	assert.Equal(t, secondNode, firstNode.Next)
+	assert.Equal(t, secondNode, thirdNode.Prev)
+	assert.Equal(t, firstNode, secondNode.Prev)
+	assert.Equal(t, thirdNode, secondNode.Next)
+}
+
+func TestDoublyLinkedListFind(t *testing.T) {
+	list := node.DoublyLinkedList{}
+
+	firstNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "Version",
+			Attrs: map[string]string{
+				"#EXT-X-VERSION": "3",
+			},
+		},
+	}
+	secondNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "MediaSequence",
+			Attrs: map[string]string{
+				"#EXT-X-MEDIA-SEQUENCE": "360948012",
+			},
+		},
+	}
+
+	list.Insert(firstNode)
+	list.Insert(secondNode)
+
+	node, found := list.Find("Version")
+
+	assert.True(t, found)
+	assert.Equal(t, firstNode, node)
+}
+
+func TestDoublyLinkedListFindAll(t *testing.T) {
+	list := node.DoublyLinkedList{}
+
+	firstNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "Version",
+			Attrs: map[string]string{
+				"#EXT-X-VERSION": "3",
+			},
+		},
+	}
+	secondNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "StreamInf",
+			Attrs: map[string]string{
+				"BANDWIDTH":         "206000",
+				"AVERAGE-BANDWIDTH": "187000",
+				"CODECS":            "mp4a.40.2,avc1.64001F",
+				"RESOLUTION":        "256x144",
+				"FRAME-RATE":        "30",
+			},
+			URI: "channel-audio_1=96000-video=80000.m3u8",
+		},
+	}
+	thirdNode := &node.Node{
+		HLSElement: &node.HLSElement{
+			Name: "StreamInf",
+			Attrs: map[string]string{
+				"BANDWIDTH":         "299000",
+				"AVERAGE-BANDWIDTH": "272000",
+				"CODECS":            "mp4a.40.2,avc1.64001F",
+				"RESOLUTION":        "384x216",
+				"FRAME-RATE":        "30",
+			},
+			URI: "channel-audio_1=96000-video=160000.m3u8",
+		},
+	}
+
+	list.Insert(firstNode)
+	list.Insert(secondNode)
+	list.Insert(thirdNode)
+
+	node := list.FindAll("StreamInf")
+	assert.Equal(t, len(node), 2)
+	assert.Equal(t, node[0], secondNode)
+	assert.Equal(t, node[1], thirdNode)
+}
+
+func TestDoublyLinkedListRemove(t *testing.T) {
+	list, nodes := newTestList("M3u8Identifier", "Version", "TargetDuration")
+
+	list.Remove(nodes[1])
+	assert.Equal(t, []string{"M3u8Identifier", "TargetDuration"}, listNames(list))
+	assert.Nil(t, nodes[1].Prev)
+	assert.Nil(t, nodes[1].Next)
+
+	list.Remove(nodes[0])
+	assert.Equal(t, nodes[2], list.Head)
+	assert.Nil(t, nodes[2].Prev)
+
+	list.Remove(nodes[2])
+	assert.Nil(t, list.Head)
+	assert.Nil(t, list.Tail)
+}
+
+func TestDoublyLinkedListReplace(t *testing.T) {
+	list, nodes := newTestList("M3u8Identifier", "Version", "TargetDuration")
+
+	first := &node.Node{HLSElement: &node.HLSElement{Name: "Comment"}}
+	list.Replace(nodes[0], first)
+	last := &node.Node{HLSElement: &node.HLSElement{Name: "Endlist"}}
+	list.Replace(nodes[2], last)
+
+	assert.Equal(t, []string{"Comment", "Version", "Endlist"}, listNames(list))
+	assert.Equal(t, first, list.Head)
+	assert.Equal(t, last, list.Tail)
+	assert.Equal(t, first, nodes[1].Prev)
+	assert.Equal(t, last, nodes[1].Next)
+	assert.Nil(t, nodes[0].Next)
+}
+
+func TestDoublyLinkedListRemoveRange(t *testing.T) {
+	list, nodes := newTestList("M3u8Identifier", "Version", "TargetDuration", "ExtInf", "Endlist")
+
+	// node2 before node1 is ignored
+	list.RemoveRange(nodes[3], nodes[1])
+	assert.Len(t, listNames(list), 5)
+
+	list.RemoveRange(nodes[1], nodes[3])
+	assert.Equal(t, []string{"M3u8Identifier", "Endlist"}, listNames(list))
+	assert.Nil(t, nodes[1].Prev)
+	assert.Nil(t, nodes[3].Next)
+	assert.Equal(t, nodes[2], nodes[1].Next)
+
+	list.RemoveRange(nodes[0], nodes[4])
+	assert.Nil(t, list.Head)
+	assert.Nil(t, list.Tail)
+}
+
+func TestDoublyLinkedListSpliceAfter(t *testing.T) {
+	list, nodes := newTestList("M3u8Identifier", "Endlist")
+	other, otherNodes := newTestList("ExtInf", "ExtInf")
+
+	list.SpliceAfter(nodes[0], other)
+	assert.Equal(t, []string{"M3u8Identifier", "ExtInf", "ExtInf", "Endlist"}, listNames(list))
+	assert.Equal(t, otherNodes[1], nodes[1].Prev)
+	assert.Nil(t, other.Head)
+	assert.Nil(t, other.Tail)
+
+	other, otherNodes = newTestList("Comment")
+	list.SpliceAfter(nodes[1], other)
+	assert.Equal(t, otherNodes[0], list.Tail)
+	assert.Equal(t, []string{"M3u8Identifier", "ExtInf", "ExtInf", "Endlist", "Comment"}, listNames(list))
+}
+
+func newTestList(names ...string) (*node.DoublyLinkedList, []*node.Node) {
+	list := &node.DoublyLinkedList{}
+	nodes := make([]*node.Node, 0, len(names))
+	for _, name := range names {
+		node := list.NewNode(name, "", nil, nil)
+		list.Insert(node)
+		nodes = append(nodes, node)
+	}
+	return list, nodes
+}
+
+// Returns the element names of the list, checking that the Prev links match the Next links.
+func listNames(list *node.DoublyLinkedList) []string {
+	names := make([]string, 0)
+	var prev *node.Node
+	for current := list.Head; current != nil; current = current.Next {
+		if current.Prev != prev {
+			return nil
+		}
+		names = append(names, current.HLSElement.Name)
+		prev = current
+	}
+	if list.Tail != prev {
+		return nil
+	}
+	return names
+}
+
+func TestDoublyLinkedListClone(t *testing.T) {
+	list, nodes := newTestList("M3u8Identifier", "ExtInf")
+	nodes[1].HLSElement.URI = "segment.ts"
+	nodes[1].HLSElement.Attrs = map[string]string{"Duration": "6"}
+	nodes[1].HLSElement.Raw = &node.Raw{Lines: []string{"#EXTINF:6,", "segment.ts"}, Attrs: map[string]string{"Duration": "6"}}
+
+	clone := list.Clone()
+	assert.Equal(t, []string{"M3u8Identifier", "ExtInf"}, listNames(clone))
+	assert.Equal(t, nodes[1].HLSElement, clone.Tail.HLSElement)
+	assert.NotSame(t, nodes[1], clone.Tail)
+
+	clone.Tail.HLSElement.Attrs["Duration"] = "4"
+	clone.Tail.HLSElement.Raw.Lines[0] = "#EXTINF:4,"
+	assert.Equal(t, "6", nodes[1].HLSElement.Attrs["Duration"])
+	assert.Equal(t, "#EXTINF:6,", nodes[1].HLSElement.Raw.Lines[0])
+	assert.Nil(t, clone.Head.HLSElement.Raw)
+}
+
+func TestDoublyLinkedListRemove_NotLinked(t *testing.T) {
+	list, nodes := newTestList("M3u8Identifier", "Version", "TargetDuration")
+	other, otherNodes := newTestList("ExtInf", "ExtInf")
+	single, singleNodes := newTestList("Comment")
+
+	// removing the same node twice
+	list.Remove(nodes[1])
+	list.Remove(nodes[1])
+	assert.Equal(t, []string{"M3u8Identifier", "TargetDuration"}, listNames(list))
+
+	// a node that was never inserted, and the nodes of other lists
+	list.Remove(list.NewNode("Endlist", "", nil, nil))
+	list.Remove(otherNodes[0])
+	list.Remove(otherNodes[1])
+	list.Remove(singleNodes[0])
+	assert.Equal(t, []string{"M3u8Identifier", "TargetDuration"}, listNames(list))
+	assert.Equal(t, []string{"ExtInf", "ExtInf"}, listNames(other))
+	assert.Equal(t, []string{"Comment"}, listNames(single))
+
+	list.Replace(nodes[1], list.NewNode("Endlist", "", nil, nil))
+	list.Replace(singleNodes[0], list.NewNode("Endlist", "", nil, nil))
+	list.RemoveRange(nodes[1], nodes[2])
+	list.RemoveRange(otherNodes[0], otherNodes[1])
+	list.SpliceAfter(nodes[1], single)
+	list.SpliceAfter(otherNodes[1], single)
+	assert.Equal(t, []string{"M3u8Identifier", "TargetDuration"}, listNames(list))
+	assert.Equal(t, []string{"ExtInf", "ExtInf"}, listNames(other))
+	assert.Equal(t, []string{"Comment"}, listNames(single))
+}
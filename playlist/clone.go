@@ -0,0 +1,38 @@
+package playlist
+
+import (
+	"maps"
+	"slices"
+)
+
+// Returns a deep copy of the playlist: its nodes, their HLSElements (including the attribute maps and raw manifest
+// lines) and the parser state fields. Changes to the copy do not affect the original playlist, so a playlist parsed
+// once can be cloned and customized per request (e.g. removing variants or rewriting URIs) without parsing it again.
+func (p *Playlist) Clone() *Playlist {
+	result := &Playlist{
+		DoublyLinkedList:      p.DoublyLinkedList.Clone(),
+		CurrentGap:            p.CurrentGap,
+		ProgramDateTime:       p.ProgramDateTime,
+		MediaSequence:         p.MediaSequence,
+		DiscontinuitySequence: p.DiscontinuitySequence,
+		SegmentsCounter:       p.SegmentsCounter,
+		DVR:                   p.DVR,
+	}
+
+	if p.CurrentSegment != nil {
+		segment := *p.CurrentSegment
+		result.CurrentSegment = &segment
+	}
+	if p.CurrentStreamInf != nil {
+		streamInf := *p.CurrentStreamInf
+		streamInf.Codecs = slices.Clone(streamInf.Codecs)
+		streamInf.Attrs = maps.Clone(streamInf.Attrs)
+		streamInf.QuotedAttrs = maps.Clone(streamInf.QuotedAttrs)
+		result.CurrentStreamInf = &streamInf
+	}
+	if p.CurrentByteRange != nil {
+		byteRange := *p.CurrentByteRange
+		result.CurrentByteRange = &byteRange
+	}
+	return result
+}
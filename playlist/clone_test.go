@@ -0,0 +1,80 @@
+package playlist_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+	file, _ := os.Open("./../mocks/multivariant/multivariant.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file, m3u8.WithLossless())
+	assert.NoError(t, err)
+	original, err := m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+
+	clone := playlist.Clone()
+	p, err := m3u8.EncodePlaylist(clone)
+	assert.NoError(t, err)
+	assert.Equal(t, original, p)
+
+	// customizing the clone leaves the original playlist untouched
+	variants := clone.Variants()
+	clone.Remove(variants[len(variants)-1])
+	variants[0].HLSElement.URI = "https://cdn.example.com/" + variants[0].HLSElement.URI
+	variants[1].HLSElement.Attrs["BANDWIDTH"] = "1"
+	variants[1].HLSElement.Raw.Lines[0] = "#EXT-X-STREAM-INF:BANDWIDTH=1"
+
+	p, err = m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Equal(t, original, p)
+	assert.Len(t, playlist.Variants(), len(variants))
+	assert.Len(t, clone.Variants(), len(variants)-1)
+
+	// the parser state fields are copied too
+	manifest := "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXT-X-MEDIA-SEQUENCE:10\n#EXTINF:6,\nsegment-10.ts\n#EXT-X-BYTERANGE:100@0\n#EXTINF:6,"
+	playlist, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+
+	clone = playlist.Clone()
+	assert.Equal(t, playlist, clone)
+	assert.NotSame(t, playlist.CurrentSegment, clone.CurrentSegment)
+	assert.NotSame(t, playlist.CurrentByteRange, clone.CurrentByteRange)
+
+	clone.CurrentSegment.Duration = 4
+	assert.Equal(t, 6.0, playlist.CurrentSegment.Duration)
+}
+
+// Cloning a parsed playlist is meant to be cheaper than parsing it again, see BenchmarkParsePlaylist.
+// Both use a Multivariant Playlist, the kind that is usually customized per request.
+func BenchmarkClone(b *testing.B) {
+	file, err := os.Open("./../mocks/multivariant/withHEVCAndFMP4.m3u8")
+	if err != nil {
+		b.Fatal(err)
+	}
+	playlist, err := m3u8.ParsePlaylist(file)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		playlist.Clone()
+	}
+}
+
+func BenchmarkParsePlaylist(b *testing.B) {
+	data, err := os.ReadFile("./../mocks/multivariant/withHEVCAndFMP4.m3u8")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		if _, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(string(data)))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
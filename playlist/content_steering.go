@@ -0,0 +1,192 @@
+package playlist
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/globocom/go-m3u8/node"
+)
+
+// METHODS FOR CONTENT STEERING
+
+// DefaultPathwayID is the Pathway of the Variant Streams without the PATHWAY-ID attribute.
+const DefaultPathwayID = "."
+
+// Pathway elements are the ones that carry the PATHWAY-ID attribute.
+var pathwayElements = []string{"Media", "StreamInf", "IFrameStreamInf"}
+
+// PathwayClone holds the data of a Pathway Clone, as listed by a Steering Server in the PATHWAY-CLONES array
+// of its Steering Manifest:
+//
+//	{"BASE-ID": "...", "ID": "...", "URI-REPLACEMENT": {"HOST": "...", "PARAMS": {...}, "PER-VARIANT-URIS": {...}, "PER-RENDITION-URIS": {...}}}
+//
+// PerVariantURIs and PerRenditionURIs are keyed by the STABLE-VARIANT-ID and STABLE-RENDITION-ID attributes.
+type PathwayClone struct {
+	BaseID           string
+	ID               string
+	Host             string
+	Params           map[string]string
+	PerVariantURIs   map[string]string
+	PerRenditionURIs map[string]string
+}
+
+// Returns the ContentSteering (#EXT-X-CONTENT-STEERING) tag as a Node if it exists, otherwise returns nil and false
+func (p *Playlist) ContentSteering() (*node.Node, bool) {
+	return p.Find("ContentSteering")
+}
+
+// Returns the Variant Streams (#EXT-X-STREAM-INF and #EXT-X-I-FRAME-STREAM-INF) and Renditions (#EXT-X-MEDIA)
+// of each Pathway in the playlist, grouped by their PATHWAY-ID attribute, in playlist order.
+//
+// Variant Streams without the PATHWAY-ID attribute belong to the DefaultPathwayID Pathway.
+// Renditions without the PATHWAY-ID attribute are shared by all Pathways, so they are not listed.
+func (p *Playlist) Pathways() map[string][]*node.Node {
+	pathways := make(map[string][]*node.Node)
+	for current := p.Head; current != nil; current = current.Next {
+		if !slices.Contains(pathwayElements, current.HLSElement.Name) {
+			continue
+		}
+
+		pathwayID := current.HLSElement.Attrs["PATHWAY-ID"]
+		if pathwayID == "" {
+			if current.HLSElement.Name == "Media" {
+				continue
+			}
+			pathwayID = DefaultPathwayID
+		}
+		pathways[pathwayID] = append(pathways[pathwayID], current)
+	}
+	return pathways
+}
+
+// Adds a copy of the Variant Streams and Renditions of the clone's base Pathway to the playlist, under the clone's ID,
+// the same way clients apply the PATHWAY-CLONES of a Steering Manifest.
+//
+// The URI of each copy is taken from PerVariantURIs (or PerRenditionURIs) by its stable ID when present. Otherwise,
+// its host is replaced by Host and the Params are set in its query, leaving the other query parameters as they are.
+// Relative URIs are first resolved against playlistURI, the URI of the Multivariant Playlist, so an error is returned
+// when Host is set and playlistURI is not an absolute URI. Each copy is inserted after the last element of the same kind
+// in the base Pathway.
+func (p *Playlist) ClonePathway(clone PathwayClone, playlistURI string) error {
+	if clone.ID == "" || clone.ID == DefaultPathwayID {
+		return fmt.Errorf("invalid pathway clone ID: %q", clone.ID)
+	}
+
+	pathways := p.Pathways()
+	if _, exists := pathways[clone.ID]; exists {
+		return fmt.Errorf("pathway %s already exists", clone.ID)
+	}
+
+	base, exists := pathways[clone.BaseID]
+	if !exists {
+		return fmt.Errorf("base pathway %s not found", clone.BaseID)
+	}
+
+	copies := make([]*node.Node, 0, len(base))
+	for _, node := range base {
+		newNode := copyNode(node)
+		newNode.HLSElement.Attrs["PATHWAY-ID"] = clone.ID
+
+		uri, err := clone.uri(node, playlistURI)
+		if err != nil {
+			return err
+		}
+		if node.HLSElement.Name == "StreamInf" {
+			newNode.HLSElement.URI = uri
+		} else if uri != "" {
+			newNode.HLSElement.Attrs["URI"] = uri
+		}
+		copies = append(copies, newNode)
+	}
+
+	// the base pathway is in playlist order, so its last element of each kind is the insertion point
+	last := make(map[string]*node.Node)
+	for _, node := range base {
+		last[node.HLSElement.Name] = node
+	}
+	for _, newNode := range copies {
+		p.InsertAfter(last[newNode.HLSElement.Name], newNode)
+		last[newNode.HLSElement.Name] = newNode
+	}
+
+	return nil
+}
+
+// Returns the URI of the given base Pathway node in the cloned Pathway.
+func (c PathwayClone) uri(baseNode *node.Node, playlistURI string) (string, error) {
+	attrs := baseNode.HLSElement.Attrs
+
+	uri := attrs["URI"]
+	if baseNode.HLSElement.Name == "StreamInf" {
+		uri = baseNode.HLSElement.URI
+	}
+
+	if baseNode.HLSElement.Name == "Media" {
+		if replacement, exists := c.PerRenditionURIs[attrs["STABLE-RENDITION-ID"]]; exists && attrs["STABLE-RENDITION-ID"] != "" {
+			return replacement, nil
+		}
+	} else if replacement, exists := c.PerVariantURIs[attrs["STABLE-VARIANT-ID"]]; exists && attrs["STABLE-VARIANT-ID"] != "" {
+		return replacement, nil
+	}
+
+	// Renditions without URI (e.g. CLOSED-CAPTIONS) have nothing to replace
+	if uri == "" {
+		return "", nil
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid URI %s in pathway %s: %w", uri, c.BaseID, err)
+	}
+
+	if c.Host != "" {
+		if !parsed.IsAbs() {
+			base, err := url.Parse(playlistURI)
+			if err != nil || !base.IsAbs() {
+				return "", fmt.Errorf("relative URI %s in pathway %s requires an absolute playlist URI, got %q", uri, c.BaseID, playlistURI)
+			}
+			parsed = base.ResolveReference(parsed)
+		}
+		parsed.Host = c.Host
+	}
+
+	if len(c.Params) > 0 {
+		parsed.RawQuery = setQueryParams(parsed.RawQuery, c.Params)
+	}
+
+	return parsed.String(), nil
+}
+
+// Sets the given params in the raw query. The other query parameters keep their original text and order,
+// and the params that are not in the query yet are added to its end, sorted by key.
+func setQueryParams(rawQuery string, params map[string]string) string {
+	parts := make([]string, 0)
+	set := make(map[string]bool, len(params))
+	if rawQuery != "" {
+		for _, part := range strings.Split(rawQuery, "&") {
+			key, _, _ := strings.Cut(part, "=")
+			if unescaped, err := url.QueryUnescape(key); err == nil {
+				key = unescaped
+			}
+
+			value, exists := params[key]
+			switch {
+			case !exists:
+				parts = append(parts, part)
+			case !set[key]:
+				parts = append(parts, url.QueryEscape(key)+"="+url.QueryEscape(value))
+				set[key] = true
+			}
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(params)) {
+		if !set[key] {
+			parts = append(parts, url.QueryEscape(key)+"="+url.QueryEscape(params[key]))
+		}
+	}
+	return strings.Join(parts, "&")
+}
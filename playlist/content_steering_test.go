@@ -0,0 +1,151 @@
+package playlist_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	pl "github.com/globocom/go-m3u8/playlist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentSteering(t *testing.T) {
+	file, _ := os.Open("./../mocks/multivariant/withContentSteering.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	node, found := playlist.ContentSteering()
+	assert.True(t, found)
+	assert.Equal(t, "https://steering.example.com/manifest.json", node.HLSElement.Attrs["SERVER-URI"])
+	assert.Equal(t, "CDN-A", node.HLSElement.Attrs["PATHWAY-ID"])
+
+	file, _ = os.Open("./../mocks/multivariant/multivariant.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	node, found = playlist.ContentSteering()
+	assert.False(t, found)
+	assert.Nil(t, node)
+}
+
+func TestPathways(t *testing.T) {
+	file, _ := os.Open("./../mocks/multivariant/withContentSteering.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	pathways := playlist.Pathways()
+	assert.Len(t, pathways, 2)
+
+	// renditions without PATHWAY-ID are shared by all pathways
+	cdnA := pathways["CDN-A"]
+	assert.Len(t, cdnA, 3)
+	assert.Equal(t, "Media", cdnA[0].HLSElement.Name)
+	assert.Equal(t, "https://cdn-a.example.com/audio.m3u8", cdnA[0].HLSElement.Attrs["URI"])
+	assert.Equal(t, "https://cdn-a.example.com/360p.m3u8", cdnA[1].HLSElement.URI)
+	assert.Equal(t, "https://cdn-a.example.com/720p.m3u8?token=abc", cdnA[2].HLSElement.URI)
+
+	cdnB := pathways["CDN-B"]
+	assert.Len(t, cdnB, 3)
+	assert.Equal(t, "https://cdn-b.example.com/audio.m3u8", cdnB[0].HLSElement.Attrs["URI"])
+	assert.Equal(t, "https://cdn-b.example.com/360p.m3u8", cdnB[1].HLSElement.URI)
+	assert.Equal(t, "https://cdn-b.example.com/720p.m3u8?token=abc", cdnB[2].HLSElement.URI)
+
+	// variants without PATHWAY-ID belong to the default pathway
+	file, _ = os.Open("./../mocks/multivariant/multivariant.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	pathways = playlist.Pathways()
+	assert.Len(t, pathways, 1)
+	assert.Equal(t, playlist.Variants(), pathways[pl.DefaultPathwayID])
+}
+
+func TestClonePathway(t *testing.T) {
+	file, _ := os.Open("./../mocks/multivariant/withContentSteering.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	err = playlist.ClonePathway(pl.PathwayClone{
+		BaseID:         "CDN-B",
+		ID:             "CDN-C",
+		Host:           "cdn-c.example.com",
+		Params:         map[string]string{"cdn": "c"},
+		PerVariantURIs: map[string]string{"720p": "https://cdn-c.example.com/hd/720p.m3u8"},
+	}, "https://origin.example.com/master.m3u8")
+	assert.NoError(t, err)
+
+	cdnC := playlist.Pathways()["CDN-C"]
+	assert.Len(t, cdnC, 3)
+	assert.Equal(t, "https://cdn-c.example.com/audio.m3u8?cdn=c", cdnC[0].HLSElement.Attrs["URI"])
+	assert.Equal(t, "audio-pt", cdnC[0].HLSElement.Attrs["STABLE-RENDITION-ID"])
+	assert.Equal(t, "https://cdn-c.example.com/360p.m3u8?cdn=c", cdnC[1].HLSElement.URI)
+	assert.Equal(t, "https://cdn-c.example.com/hd/720p.m3u8", cdnC[2].HLSElement.URI)
+	assert.Equal(t, "2560000", cdnC[2].HLSElement.Attrs["BANDWIDTH"])
+
+	// clones are inserted after the last element of the same kind in the base pathway
+	cdnB := playlist.Pathways()["CDN-B"]
+	assert.Equal(t, cdnC[0], cdnB[0].Next)
+	assert.Equal(t, cdnC[1], cdnB[2].Next)
+	assert.Equal(t, cdnC[2], cdnC[1].Next)
+	assert.Equal(t, cdnC[2], playlist.Tail)
+
+	// the base pathway is not modified
+	assert.Equal(t, "https://cdn-b.example.com/360p.m3u8", cdnB[1].HLSElement.URI)
+	assert.Equal(t, "CDN-B", cdnB[1].HLSElement.Attrs["PATHWAY-ID"])
+
+	// clone with an existing ID
+	err = playlist.ClonePathway(pl.PathwayClone{BaseID: "CDN-A", ID: "CDN-C"}, "")
+	assert.Error(t, err)
+
+	// clone of a missing base pathway
+	err = playlist.ClonePathway(pl.PathwayClone{BaseID: "CDN-D", ID: "CDN-E"}, "")
+	assert.Error(t, err)
+
+	// clone without ID
+	err = playlist.ClonePathway(pl.PathwayClone{BaseID: "CDN-A"}, "")
+	assert.Error(t, err)
+
+	// only the listed params change, the rest of the query is kept as written
+	err = playlist.ClonePathway(pl.PathwayClone{
+		BaseID: "CDN-A",
+		ID:     "CDN-D",
+		Host:   "cdn-d.example.com",
+		Params: map[string]string{"cdn": "d e", "token": "xyz"},
+	}, "")
+	assert.NoError(t, err)
+	cdnD := playlist.Pathways()["CDN-D"]
+	assert.Equal(t, "https://cdn-d.example.com/360p.m3u8?cdn=d+e&token=xyz", cdnD[1].HLSElement.URI)
+	assert.Equal(t, "https://cdn-d.example.com/720p.m3u8?token=xyz&cdn=d+e", cdnD[2].HLSElement.URI)
+}
+
+func TestClonePathway_RelativeURIs(t *testing.T) {
+	manifest := `#EXTM3U
+#EXT-X-CONTENT-STEERING:SERVER-URI="https://steering.example.com/manifest.json",PATHWAY-ID="CDN-A"
+#EXT-X-STREAM-INF:BANDWIDTH=1280000,PATHWAY-ID="CDN-A"
+360p/index.m3u8?b=2%2C3&a=1
+`
+	clone := pl.PathwayClone{BaseID: "CDN-A", ID: "CDN-B", Host: "cdn-b.example.com", Params: map[string]string{"cdn": "b"}}
+
+	// relative URIs are resolved against the playlist URI before their host is replaced
+	playlist, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+	err = playlist.ClonePathway(clone, "https://cdn-a.example.com/live/master.m3u8")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn-b.example.com/live/360p/index.m3u8?b=2%2C3&a=1&cdn=b", playlist.Pathways()["CDN-B"][0].HLSElement.URI)
+
+	// the host of a relative URI can not be replaced without an absolute playlist URI
+	playlist, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+	err = playlist.ClonePathway(clone, "master.m3u8")
+	assert.Error(t, err)
+	assert.Len(t, playlist.Pathways(), 1)
+
+	// without Host, relative URIs stay relative
+	playlist, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+	err = playlist.ClonePathway(pl.PathwayClone{BaseID: "CDN-A", ID: "CDN-B", Params: map[string]string{"cdn": "b"}}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "360p/index.m3u8?b=2%2C3&a=1&cdn=b", playlist.Pathways()["CDN-B"][0].HLSElement.URI)
+}
@@ -0,0 +1,270 @@
+package playlist
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/globocom/go-m3u8/node"
+)
+
+// METHODS FOR PLAYLIST DELTA UPDATES
+
+// Playlist-level tags apply to the whole playlist rather than to a segment, so they are never replaced by the
+// Skip (#EXT-X-SKIP) tag in a Playlist Delta Update.
+var playlistLevelElements = []string{
+	"M3u8Identifier",
+	"Version",
+	"TargetDuration",
+	"MediaSequence",
+	"DiscontinuitySequence",
+	"PlaylistType",
+	"IFramesOnly",
+	"IndependentSegments",
+	"VariableDefine",
+	"Start",
+	"ServerControl",
+	"PartInf",
+	"UspTimestampMap",
+	"Skip",
+	"Endlist",
+	"ContentSteering",
+	"SessionData",
+}
+
+// Returns true if the given element name is a playlist-level tag, which applies to the whole playlist
+// rather than to a segment (e.g. TargetDuration, Endlist).
+func IsPlaylistLevel(elementName string) bool {
+	return slices.Contains(playlistLevelElements, elementName)
+}
+
+// Returns the Skip (#EXT-X-SKIP) tag as a Node if it exists, otherwise returns nil and false
+func (p *Playlist) SkipTag() (*node.Node, bool) {
+	return p.Find("Skip")
+}
+
+// Returns a Playlist Delta Update of the playlist, as served for _HLS_skip requests.
+//
+// Media Segments that end at least skipBoundary seconds before the end of the playlist are replaced
+// by a single Skip (#EXT-X-SKIP) tag, along with the Media Segment tags applied to them.
+// The Key (#EXT-X-KEY) and Map (#EXT-X-MAP) tags in effect for the first remaining segment are kept after the Skip tag.
+//
+// When skipDateRanges is true (i.e. _HLS_skip=v2), DateRange (#EXT-X-DATERANGE) tags of the skipped segments are also
+// replaced, and recentlyRemovedDateRanges are listed in the RECENTLY-REMOVED-DATERANGES attribute.
+// Otherwise, they are kept before the Skip tag.
+// The Version (#EXT-X-VERSION) tag of the delta is raised to the version its Skip tag requires (see RequiredVersion).
+//
+// Returns an error if the playlist does not declare the CAN-SKIP-UNTIL attribute of the ServerControl (#EXT-X-SERVER-CONTROL) tag,
+// as clients can't request delta updates of it. The original playlist is not modified.
+func (p *Playlist) DeltaUpdate(skipBoundary float64, skipDateRanges bool, recentlyRemovedDateRanges []string) (*Playlist, error) {
+	if skipBoundary <= 0 {
+		return nil, fmt.Errorf("invalid skip boundary: %f", skipBoundary)
+	}
+
+	if serverControl, found := p.ServerControl(); !found || serverControl.CanSkipUntil <= 0 {
+		return nil, fmt.Errorf("playlist does not declare CAN-SKIP-UNTIL")
+	}
+
+	if _, found := p.SkipTag(); found {
+		return nil, fmt.Errorf("playlist is already a delta update")
+	}
+
+	segments := p.Segments()
+	total := 0.0
+	for _, segment := range segments {
+		total += segmentDuration(segment)
+	}
+
+	skipped, end := 0, 0.0
+	for _, segment := range segments {
+		end += segmentDuration(segment)
+		if total-end < skipBoundary {
+			break
+		}
+		skipped++
+	}
+
+	delta := NewPlaylist()
+	if skipped == 0 {
+		for current := p.Head; current != nil; current = current.Next {
+			delta.Insert(copyNode(current))
+		}
+		delta.syncState()
+		return delta, nil
+	}
+
+	regionStart := segmentsRegionStart(segments[0])
+	lastSkipped := segments[skipped-1]
+
+	var key, initSection *node.Node
+	inRegion := false
+	for current := p.Head; current != nil; current = current.Next {
+		if current == regionStart {
+			inRegion = true
+		}
+
+		if !inRegion || slices.Contains(playlistLevelElements, current.HLSElement.Name) {
+			delta.Insert(copyNode(current))
+			continue
+		}
+
+		switch current.HLSElement.Name {
+		case "DateRange":
+			if !skipDateRanges {
+				delta.Insert(copyNode(current))
+			}
+		case "Key":
+			key = current
+		case "Map":
+			initSection = current
+		}
+
+		if current != lastSkipped {
+			continue
+		}
+
+		inRegion = false
+		skipAttrs := map[string]string{"SKIPPED-SEGMENTS": strconv.Itoa(skipped)}
+		if skipDateRanges {
+			skipAttrs["RECENTLY-REMOVED-DATERANGES"] = strings.Join(recentlyRemovedDateRanges, "\t")
+		}
+		delta.Insert(delta.NewNode("Skip", "", skipAttrs, nil))
+
+		for _, node := range []*node.Node{key, initSection} {
+			if node != nil {
+				delta.Insert(copyNode(node))
+			}
+		}
+	}
+
+	delta.UpdateVersion()
+	delta.syncState()
+	return delta, nil
+}
+
+// Rebuilds the complete playlist from a Playlist Delta Update, using the playlist as the previously loaded full playlist.
+//
+// The segments replaced by the Skip (#EXT-X-SKIP) tag are taken from the playlist, along with their Media Segment tags.
+// DateRange (#EXT-X-DATERANGE) tags listed in RECENTLY-REMOVED-DATERANGES are dropped.
+// If delta has no Skip tag, it is already a full playlist and a copy of it is returned.
+//
+// Neither the playlist nor the delta are modified.
+func (p *Playlist) ApplyDeltaUpdate(delta *Playlist) (*Playlist, error) {
+	result := NewPlaylist()
+
+	skipNode, found := delta.SkipTag()
+	if !found {
+		for current := delta.Head; current != nil; current = current.Next {
+			result.Insert(copyNode(current))
+		}
+		result.syncState()
+		return result, nil
+	}
+
+	skipped, err := strconv.Atoi(skipNode.HLSElement.Attrs["SKIPPED-SEGMENTS"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid SKIPPED-SEGMENTS attribute: %w", err)
+	}
+
+	first := delta.MediaSequence - p.MediaSequence
+	segments := p.Segments()
+	if first < 0 || first+skipped > len(segments) {
+		return nil, fmt.Errorf("skipped segments %d to %d are not in the playlist", delta.MediaSequence, delta.MediaSequence+skipped-1)
+	}
+
+	// the skipped region starts right after the last segment before it
+	var regionStart *node.Node
+	if first == 0 {
+		regionStart = segmentsRegionStart(segments[0])
+	} else {
+		regionStart = segments[first-1].Next
+	}
+	lastSkipped := segments[first+skipped-1]
+
+	removedDateRanges := make([]string, 0)
+	if value := skipNode.HLSElement.Attrs["RECENTLY-REMOVED-DATERANGES"]; value != "" {
+		removedDateRanges = strings.Split(value, "\t")
+	}
+	region := make([]*node.Node, 0)
+	regionDateRanges := make([]string, 0)
+	var key, initSection *node.Node
+	for current := regionStart; current != nil; current = current.Next {
+		if !slices.Contains(playlistLevelElements, current.HLSElement.Name) {
+			switch current.HLSElement.Name {
+			case "DateRange":
+				regionDateRanges = append(regionDateRanges, current.HLSElement.Attrs["ID"])
+			case "Key":
+				key = current
+			case "Map":
+				initSection = current
+			}
+
+			if current.HLSElement.Name != "DateRange" || !slices.Contains(removedDateRanges, current.HLSElement.Attrs["ID"]) {
+				region = append(region, current)
+			}
+		}
+
+		if current == lastSkipped {
+			break
+		}
+	}
+
+	// delta nodes before the Skip tag, without the DateRange tags that are restored with the skipped region
+	for current := delta.Head; current != skipNode; current = current.Next {
+		if current.HLSElement.Name == "DateRange" && slices.Contains(regionDateRanges, current.HLSElement.Attrs["ID"]) {
+			continue
+		}
+		result.Insert(copyNode(current))
+	}
+
+	for _, node := range region {
+		result.Insert(copyNode(node))
+	}
+
+	// delta nodes after the Skip tag, without the Key and Map tags that were kept for the first remaining segment
+	leading := true
+	for current := skipNode.Next; current != nil; current = current.Next {
+		if leading && (isSameElement(current, key) || isSameElement(current, initSection)) {
+			continue
+		}
+		if current.HLSElement.Name != "Key" && current.HLSElement.Name != "Map" {
+			leading = false
+		}
+		result.Insert(copyNode(current))
+	}
+
+	result.syncState()
+	return result, nil
+}
+
+// Returns the first node of the Media Segment tags applied to the given segment,
+// i.e. the node right after the playlist-level tags that precede it.
+func segmentsRegionStart(segment *node.Node) *node.Node {
+	start := segment
+	for current := segment.Prev; current != nil; current = current.Prev {
+		if slices.Contains(playlistLevelElements, current.HLSElement.Name) {
+			break
+		}
+		start = current
+	}
+	return start
+}
+
+// Returns true if both nodes represent the same HLS element (same name and attributes).
+func isSameElement(node, other *node.Node) bool {
+	if node == nil || other == nil {
+		return false
+	}
+	return node.HLSElement.Name == other.HLSElement.Name && maps.Equal(node.HLSElement.Attrs, other.HLSElement.Attrs)
+}
+
+// Returns the duration (in seconds) of the given segment (#EXTINF) node.
+func segmentDuration(segment *node.Node) float64 {
+	duration, err := strconv.ParseFloat(segment.HLSElement.Attrs["Duration"], 64)
+	if err != nil {
+		return 0
+	}
+	return duration
+}
@@ -0,0 +1,180 @@
+package playlist_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeltaUpdate(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/lowlatency/withDeltaUpdateSupport.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	delta, err := playlist.DeltaUpdate(12, false, nil)
+	assert.NoError(t, err)
+
+	expectedDelta := `#EXTM3U
+#EXT-X-VERSION:9
+#EXT-X-TARGETDURATION:4
+#EXT-X-SERVER-CONTROL:CAN-SKIP-UNTIL=12.0,CAN-SKIP-DATERANGES=YES,PART-HOLD-BACK=3.012,CAN-BLOCK-RELOAD=YES
+#EXT-X-PART-INF:PART-TARGET=1.004
+#EXT-X-MEDIA-SEQUENCE:266
+#EXT-X-DATERANGE:ID="chapter-1",START-DATE="2025-05-16T13:33:31.966746Z",PLANNED-DURATION=8
+#EXT-X-SKIP:SKIPPED-SEGMENTS=4
+#EXT-X-KEY:METHOD=SAMPLE-AES,URI="skd://key-2",KEYFORMAT="com.apple.streamingkeydelivery",KEYFORMATVERSIONS="1"
+#EXT-X-MAP:URI="init.mp4"
+#EXTINF:4.00008
+fileSequence270.mp4
+#EXTINF:4.00008
+fileSequence271.mp4
+#EXT-X-PART:DURATION=1.00002,URI="filePart272.0.mp4",INDEPENDENT=YES
+#EXT-X-PART:DURATION=1.00002,URI="filePart272.1.mp4"
+#EXT-X-PART:DURATION=1.00002,URI="filePart272.2.mp4",INDEPENDENT=YES
+#EXT-X-PART:DURATION=1.00002,URI="filePart272.3.mp4"
+#EXTINF:4.00008
+fileSequence272.mp4
+#EXT-X-PART:DURATION=1.00002,URI="filePart273.0.mp4",INDEPENDENT=YES
+#EXT-X-PRELOAD-HINT:TYPE=PART,URI="filePart273.1.mp4"
+`
+	manifest, err := m3u8.EncodePlaylist(delta)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedDelta, manifest)
+
+	assert.Equal(t, 266, delta.MediaSequence)
+	assert.Equal(t, 7, delta.SegmentsCounter)
+	assert.Equal(t, "270", delta.Segments()[0].HLSElement.Details["MediaSequence"])
+
+	// the original playlist is not modified
+	assert.Len(t, playlist.Segments(), 7)
+	_, found := playlist.SkipTag()
+	assert.False(t, found)
+
+	// skip boundary beyond the playlist duration
+	delta, err = playlist.DeltaUpdate(60, false, nil)
+	assert.NoError(t, err)
+	_, found = delta.SkipTag()
+	assert.False(t, found)
+	assert.Len(t, delta.Segments(), 7)
+
+	// invalid skip boundary
+	_, err = playlist.DeltaUpdate(0, false, nil)
+	assert.Error(t, err)
+
+	// playlist without CAN-SKIP-UNTIL
+	file, _ = os.Open("./../mocks/media/media.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	_, err = playlist.DeltaUpdate(12, false, nil)
+	assert.ErrorContains(t, err, "playlist does not declare CAN-SKIP-UNTIL")
+}
+
+func TestDeltaUpdate_SkipDateRanges(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/lowlatency/withDeltaUpdateSupport.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	delta, err := playlist.DeltaUpdate(12, true, []string{"chapter-0"})
+	assert.NoError(t, err)
+
+	skipNode, found := delta.SkipTag()
+	assert.True(t, found)
+	assert.Equal(t, "4", skipNode.HLSElement.Attrs["SKIPPED-SEGMENTS"])
+	assert.Equal(t, "chapter-0", skipNode.HLSElement.Attrs["RECENTLY-REMOVED-DATERANGES"])
+	assert.Empty(t, delta.FindAll("DateRange"))
+	assert.Equal(t, 9, playlist.Version())
+	assert.Equal(t, 10, delta.Version())
+
+	// the recently removed date ranges are separated by tabs
+	delta, err = playlist.DeltaUpdate(12, true, []string{"chapter-0", "ad-0"})
+	assert.NoError(t, err)
+
+	manifest, err := m3u8.EncodePlaylist(delta)
+	assert.NoError(t, err)
+	assert.Contains(t, manifest, "#EXT-X-SKIP:SKIPPED-SEGMENTS=4,RECENTLY-REMOVED-DATERANGES=\"chapter-0\tad-0\"\n")
+}
+
+func TestApplyDeltaUpdate(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/lowlatency/withDeltaUpdateSupport.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	expected, err := m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+
+	for _, skipDateRanges := range []bool{false, true} {
+		delta, err := playlist.DeltaUpdate(12, skipDateRanges, nil)
+		assert.NoError(t, err)
+
+		// the delta goes through the wire before being merged
+		manifest, err := m3u8.EncodePlaylist(delta)
+		assert.NoError(t, err)
+		delta, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+		assert.NoError(t, err)
+
+		full, err := playlist.ApplyDeltaUpdate(delta)
+		assert.NoError(t, err)
+
+		rebuilt, err := m3u8.EncodePlaylist(full)
+		assert.NoError(t, err)
+		if skipDateRanges {
+			// the headers come from the delta, whose version was raised for its Skip tag
+			assert.Equal(t, strings.Replace(expected, "#EXT-X-VERSION:9\n", "#EXT-X-VERSION:10\n", 1), rebuilt)
+		} else {
+			assert.Equal(t, expected, rebuilt)
+		}
+		assert.Equal(t, playlist.SegmentsCounter, full.SegmentsCounter)
+		assert.Equal(t, playlist.DVR, full.DVR)
+		assert.Equal(t, playlist.ProgramDateTime, full.ProgramDateTime)
+	}
+}
+
+func TestApplyDeltaUpdate_DateRangeWithoutID(t *testing.T) {
+	data, err := os.ReadFile("./../mocks/media/lowlatency/withDeltaUpdateSupport.m3u8")
+	assert.NoError(t, err)
+	manifest := strings.Replace(string(data), `ID="chapter-1",`, "", 1)
+
+	playlist, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+
+	// a Skip tag without RECENTLY-REMOVED-DATERANGES removes no DateRange
+	delta, err := playlist.DeltaUpdate(12, false, nil)
+	assert.NoError(t, err)
+
+	full, err := playlist.ApplyDeltaUpdate(delta)
+	assert.NoError(t, err)
+	assert.Len(t, full.FindAll("DateRange"), 1)
+}
+
+func TestApplyDeltaUpdate_RecentlyRemovedDateRanges(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/lowlatency/withDeltaUpdateSupport.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	file, _ = os.Open("./../mocks/media/lowlatency/withSkip.m3u8")
+	delta, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	full, err := playlist.ApplyDeltaUpdate(delta)
+	assert.NoError(t, err)
+
+	segments := full.Segments()
+	assert.Len(t, segments, 7)
+	assert.Equal(t, "fileSequence266.mp4", segments[0].HLSElement.URI)
+	assert.Equal(t, "fileSequence272.mp4", segments[6].HLSElement.URI)
+	assert.Len(t, full.FindAll("DateRange"), 1)
+	assert.Len(t, full.EncryptionTags(), 2)
+
+	// delta whose skipped segments are not in the playlist
+	file, _ = os.Open("./../mocks/media/media.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	_, err = playlist.ApplyDeltaUpdate(delta)
+	assert.Error(t, err)
+}
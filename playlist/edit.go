@@ -0,0 +1,55 @@
+package playlist
+
+import "github.com/globocom/go-m3u8/node"
+
+// METHODS FOR EDITING THE PLAYLIST
+//
+// These methods wrap the ones of the doubly linked list, and recompute the playlist counters (e.g. SegmentsCounter, DVR)
+// after each change. They are meant for parsed playlists: the pending parser state (e.g. CurrentSegment) is cleared.
+// Insert is not wrapped, since the parsers append nodes with it while their state is pending.
+//
+// Only the playlist counters are recomputed: the Details of the segments after the change (e.g. MediaSequence,
+// ProgramDateTime) keep the values they were parsed with.
+
+// Inserts newNode after node in the playlist.
+func (p *Playlist) InsertAfter(node, newNode *node.Node) {
+	p.DoublyLinkedList.InsertAfter(node, newNode)
+	p.syncState()
+}
+
+// Inserts newNode before node in the playlist.
+func (p *Playlist) InsertBefore(node, newNode *node.Node) {
+	p.DoublyLinkedList.InsertBefore(node, newNode)
+	p.syncState()
+}
+
+// Inserts newNode between node1 and node2 in the playlist.
+func (p *Playlist) InsertBetween(node1, node2, newNode *node.Node) {
+	p.DoublyLinkedList.InsertBetween(node1, node2, newNode)
+	p.syncState()
+}
+
+// Removes node from the playlist.
+func (p *Playlist) Remove(node *node.Node) {
+	p.DoublyLinkedList.Remove(node)
+	p.syncState()
+}
+
+// Replaces oldNode with newNode in the playlist.
+func (p *Playlist) Replace(oldNode, newNode *node.Node) {
+	p.DoublyLinkedList.Replace(oldNode, newNode)
+	p.syncState()
+}
+
+// Removes the nodes from node1 to node2 (both included) from the playlist, e.g. the segments of an Ad Break.
+func (p *Playlist) RemoveRange(node1, node2 *node.Node) {
+	p.DoublyLinkedList.RemoveRange(node1, node2)
+	p.syncState()
+}
+
+// Moves all nodes of otherList after node in the playlist, leaving otherList empty.
+// To splice another Playlist, pass its DoublyLinkedList; the counters of that Playlist are not updated.
+func (p *Playlist) SpliceAfter(node *node.Node, otherList *node.DoublyLinkedList) {
+	p.DoublyLinkedList.SpliceAfter(node, otherList)
+	p.syncState()
+}
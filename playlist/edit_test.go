@@ -0,0 +1,89 @@
+package playlist_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaylistEdit(t *testing.T) {
+	manifest := `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXTINF:6,
+segment-1.ts
+#EXTINF:6,
+segment-2.ts
+#EXTINF:6,
+segment-3.ts
+#EXTINF:4,
+segment-4.ts`
+
+	playlist, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, playlist.SegmentsCounter)
+	assert.Equal(t, 22.0, playlist.DVR)
+
+	// cut segments 2 and 3 out, and splice two ad segments in their place
+	segments := playlist.Segments()
+	playlist.RemoveRange(segments[1], segments[2])
+	assert.Equal(t, 2, playlist.SegmentsCounter)
+	assert.Equal(t, 10.0, playlist.DVR)
+
+	ads, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader("#EXTINF:5,\nad-1.ts\n#EXTINF:5,\nad-2.ts")))
+	assert.NoError(t, err)
+	playlist.SpliceAfter(segments[0], ads.DoublyLinkedList)
+	assert.Equal(t, 4, playlist.SegmentsCounter)
+	assert.Equal(t, 20.0, playlist.DVR)
+	assert.Nil(t, ads.Head)
+
+	discontinuity := playlist.NewNode("Discontinuity", "", map[string]string{"#EXT-X-DISCONTINUITY": ""}, nil)
+	playlist.InsertAfter(segments[0], discontinuity)
+
+	// replace the last segment, which is the tail of the list
+	last := playlist.NewNode("ExtInf", "segment-5.ts", map[string]string{"Duration": "6", "Title": ""}, map[string]string{})
+	playlist.Replace(segments[3], last)
+	assert.Equal(t, last, playlist.Tail)
+	assert.Equal(t, 22.0, playlist.DVR)
+
+	playlist.Remove(discontinuity)
+	playlist.Remove(segments[0])
+	assert.Equal(t, 3, playlist.SegmentsCounter)
+	assert.Equal(t, 16.0, playlist.DVR)
+
+	// inserted segments are counted too
+	bumper := playlist.NewNode("ExtInf", "bumper.ts", map[string]string{"Duration": "2", "Title": ""}, map[string]string{})
+	playlist.InsertBefore(last, bumper)
+	assert.Equal(t, 4, playlist.SegmentsCounter)
+	assert.Equal(t, 18.0, playlist.DVR)
+	playlist.Remove(bumper)
+
+	p, err := m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Equal(t, "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXTINF:5\nad-1.ts\n#EXTINF:5\nad-2.ts\n#EXTINF:6\nsegment-5.ts\n", p)
+}
+
+func TestPlaylistEdit_NotLinked(t *testing.T) {
+	playlist, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader("#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\nsegment-1.ts")))
+	assert.NoError(t, err)
+	other, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader("#EXTINF:6,\nad-1.ts")))
+	assert.NoError(t, err)
+
+	segment := playlist.Segments()[0]
+	playlist.Remove(segment)
+	playlist.Remove(segment)
+	playlist.Remove(other.Segments()[0])
+	playlist.Replace(segment, playlist.NewNode("Endlist", "", map[string]string{"#EXT-X-ENDLIST": ""}, nil))
+	assert.Equal(t, 0, playlist.SegmentsCounter)
+
+	p, err := m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Equal(t, "#EXTM3U\n#EXT-X-TARGETDURATION:6\n", p)
+
+	p, err = m3u8.EncodePlaylist(other)
+	assert.NoError(t, err)
+	assert.Equal(t, "#EXTINF:6\nad-1.ts\n", p)
+}
@@ -2,14 +2,16 @@ package playlist
 
 import (
 	"fmt"
+	"io"
+	"maps"
 	"math"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/globocom/go-m3u8/internal"
-	"github.com/rs/zerolog/log"
+	"github.com/globocom/go-m3u8/node"
 )
 
 // METHODS FOR DECODING MULTI-LINE TAGS
@@ -18,6 +20,11 @@ import (
 //
 //	#EXT-X-STREAM-INF:<attribute-list>
 //	<URI>
+//
+// Attrs holds every attribute in the attribute-list, including the ones without a typed field (e.g. SCORE,
+// SUPPLEMENTAL-CODECS and client-defined X- attributes), so that none of them is lost. The StreamInf node is built
+// from Attrs only: the typed fields are derived from it when parsing, for reading convenience.
+// QuotedAttrs tells, for each attribute in the attribute-list, whether its value was a quoted-string.
 type StreamInfData struct {
 	Codecs           []string
 	Bandwidth        string
@@ -30,6 +37,8 @@ type StreamInfData struct {
 	Video            string
 	Subtitles        string
 	ClosedCaptions   string
+	Attrs            map[string]string
+	QuotedAttrs      map[string]bool
 }
 
 // ExtInfData holds data for ExtInf HLS element, whose format in manifest is multi-line:
@@ -44,6 +53,36 @@ type ExtInfData struct {
 	Title           string
 }
 
+// ServerControlData holds the typed attributes of the ServerControl HLS Element:
+//
+//	#EXT-X-SERVER-CONTROL:<attribute-list>
+type ServerControlData struct {
+	CanSkipUntil      float64
+	CanSkipDateRanges bool
+	HoldBack          float64
+	PartHoldBack      float64
+	CanBlockReload    bool
+}
+
+// StartData holds the typed attributes of the Start HLS Element:
+//
+//	#EXT-X-START:<attribute-list>
+type StartData struct {
+	TimeOffset float64
+	Precise    bool
+}
+
+// ByteRangeData holds data for a sub-range of a resource, whose format in manifest is:
+//
+//	#EXT-X-BYTERANGE:<n>[@<o>]
+//
+// When the offset is not present in the manifest, HasOffset is false and the offset must be resolved from the previous segment.
+type ByteRangeData struct {
+	Length    int64
+	Offset    int64
+	HasOffset bool
+}
+
 // Parser function that returns new StreamInfData object.
 func GetStreamInfData(mappedAttr map[string]string) *StreamInfData {
 	return &StreamInfData{
@@ -58,15 +97,15 @@ func GetStreamInfData(mappedAttr map[string]string) *StreamInfData {
 		Video:            mappedAttr["VIDEO"],
 		Subtitles:        mappedAttr["SUBTITLES"],
 		ClosedCaptions:   mappedAttr["CLOSED-CAPTIONS"],
+		Attrs:            mappedAttr,
 	}
 }
 
 // Parser function that returns new ExtInfData object.
-func GetExtInfData(duration, title string, playlistMediaSequence, playlistSegmentsCounter int, playlistDVR float64, playlistPDT time.Time) *ExtInfData {
+func GetExtInfData(duration, title string, playlistMediaSequence, playlistSegmentsCounter int, playlistDVR float64, playlistPDT time.Time) (*ExtInfData, error) {
 	floatDuration, err := strconv.ParseFloat(duration, 64)
 	if err != nil {
-		log.Error().Str("service", "go-m3u8/playlist/helpers.go").Err(err).Msgf("failed to parse duration for segment: %s", duration)
-		return &ExtInfData{}
+		return nil, fmt.Errorf("invalid segment duration %s: %w", duration, err)
 	}
 
 	currentDVRInNanoseconds := int(playlistDVR * float64(time.Second))
@@ -77,17 +116,122 @@ func GetExtInfData(duration, title string, playlistMediaSequence, playlistSegmen
 		Title:           title,
 		MediaSequence:   playlistMediaSequence + playlistSegmentsCounter,
 		ProgramDateTime: segmentProgramDateTime,
+	}, nil
+}
+
+// Parser function that returns new ServerControlData object.
+// Decimal-floating-point attributes that are absent are set to zero, and enumerated-string attributes are true only when YES.
+func GetServerControlData(mappedAttr map[string]string) (*ServerControlData, error) {
+	data := &ServerControlData{
+		CanSkipDateRanges: mappedAttr["CAN-SKIP-DATERANGES"] == "YES",
+		CanBlockReload:    mappedAttr["CAN-BLOCK-RELOAD"] == "YES",
+	}
+
+	floatAttrs := map[string]*float64{
+		"CAN-SKIP-UNTIL": &data.CanSkipUntil,
+		"HOLD-BACK":      &data.HoldBack,
+		"PART-HOLD-BACK": &data.PartHoldBack,
+	}
+	for key, field := range floatAttrs {
+		value, exists := mappedAttr[key]
+		if !exists || value == "" {
+			continue
+		}
+
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s attribute value: %s", key, value)
+		}
+		*field = parsed
+	}
+
+	return data, nil
+}
+
+// Parser function that returns new StartData object.
+// PRECISE is true only when YES, and any value other than YES or NO is invalid.
+func GetStartData(mappedAttr map[string]string) (*StartData, error) {
+	timeOffset, err := strconv.ParseFloat(mappedAttr["TIME-OFFSET"], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TIME-OFFSET attribute value: %s", mappedAttr["TIME-OFFSET"])
+	}
+
+	precise, exists := mappedAttr["PRECISE"]
+	if exists && precise != "YES" && precise != "NO" {
+		return nil, fmt.Errorf("invalid PRECISE attribute value: %s", precise)
+	}
+
+	return &StartData{TimeOffset: timeOffset, Precise: precise == "YES"}, nil
+}
+
+// Parser function that returns new ByteRangeData object from a "<n>[@<o>]" value.
+func GetByteRangeData(value string) (*ByteRangeData, error) {
+	length, offset, hasOffset := strings.Cut(strings.TrimSpace(value), "@")
+
+	data := &ByteRangeData{HasOffset: hasOffset}
+
+	var err error
+	if data.Length, err = strconv.ParseInt(length, 10, 64); err != nil || data.Length < 0 {
+		return nil, fmt.Errorf("invalid byte range length: %s", value)
+	}
+
+	if hasOffset {
+		if data.Offset, err = strconv.ParseInt(offset, 10, 64); err != nil || data.Offset < 0 {
+			return nil, fmt.Errorf("invalid byte range offset: %s", value)
+		}
+	}
+
+	return data, nil
+}
+
+// Formats the byte range back into its "<n>[@<o>]" manifest value.
+func (b *ByteRangeData) String() string {
+	if b.HasOffset {
+		return fmt.Sprintf("%d@%d", b.Length, b.Offset)
+	}
+	return strconv.FormatInt(b.Length, 10)
+}
+
+// Attaches the pending ByteRange (#EXT-X-BYTERANGE) to the given segment node.
+// If the offset is not present, the sub-range begins at the next byte following the sub-range of the previous segment,
+// which MUST be a sub-range of the same resource.
+// The segment is already in the playlist, and in its counters, when an error is returned, so that it can still be kept
+// by the lenient decoder, which drops it from both otherwise.
+func attachByteRange(p *Playlist, segment *node.Node) error {
+	byteRange := p.CurrentByteRange
+	p.CurrentByteRange = nil
+
+	if !byteRange.HasOffset {
+		previous := p.FindPreviousSegment(segment)
+		if previous == nil || previous.HLSElement.URI != segment.HLSElement.URI {
+			return fmt.Errorf("byte range without offset requires a previous sub-range of %s", segment.HLSElement.URI)
+		}
+
+		previousRange, found := p.ByteRange(previous)
+		if !found {
+			return fmt.Errorf("byte range without offset requires a previous sub-range of %s", segment.HLSElement.URI)
+		}
+		byteRange.Offset = previousRange.Offset + previousRange.Length
 	}
+
+	segment.HLSElement.Details["ByteRangeLength"] = strconv.FormatInt(byteRange.Length, 10)
+	segment.HLSElement.Details["ByteRangeOffset"] = strconv.FormatInt(byteRange.Offset, 10)
+	return nil
 }
 
 // Handles HLS Elements whose format in manifest are multi-line: tag + uri.
 // The URI line that follows the EXT-X-STREAM-INF and EXTINF tags is REQUIRED.
+// Blank lines and lines that start with '#' are never URIs, so they are ignored.
 func HandleMultiLineHLSElements(line string, p *Playlist) error {
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
 	switch {
 	// handle EXTINF
 	case p.CurrentSegment != nil:
-		p.Insert(&internal.Node{
-			HLSElement: &internal.HLSElement{
+		segment := &node.Node{
+			HLSElement: &node.HLSElement{
 				Name: "ExtInf",
 				URI:  line,
 				Attrs: map[string]string{
@@ -99,29 +243,28 @@ func HandleMultiLineHLSElements(line string, p *Playlist) error {
 					"ProgramDateTime": p.CurrentSegment.ProgramDateTime.Format(time.RFC3339Nano),
 				},
 			},
-		})
+		}
+		if p.CurrentGap {
+			segment.HLSElement.Attrs["Gap"] = "YES"
+			p.CurrentGap = false
+		}
+		p.Insert(segment)
 		p.CurrentSegment = nil
+
+		if p.CurrentByteRange != nil {
+			segment.HLSElement.Attrs["ByteRange"] = p.CurrentByteRange.String()
+			return attachByteRange(p, segment)
+		}
 		return nil
 
 	// handle EXT-X-STREAM-INF
 	case p.CurrentStreamInf != nil:
-		p.Insert(&internal.Node{
-			HLSElement: &internal.HLSElement{
-				Name: "StreamInf",
-				URI:  line,
-				Attrs: map[string]string{
-					"BANDWIDTH":         p.CurrentStreamInf.Bandwidth,
-					"AVERAGE-BANDWIDTH": p.CurrentStreamInf.AverageBandwidth,
-					"CODECS":            strings.Join(p.CurrentStreamInf.Codecs, ","),
-					"RESOLUTION":        p.CurrentStreamInf.Resolution,
-					"FRAME-RATE":        p.CurrentStreamInf.FrameRate,
-					"HDCP-LEVEL":        p.CurrentStreamInf.HDCPLevel,
-					"VIDEO-RANGE":       p.CurrentStreamInf.VideoRange,
-					"AUDIO":             p.CurrentStreamInf.Audio,
-					"VIDEO":             p.CurrentStreamInf.Video,
-					"SUBTITLES":         p.CurrentStreamInf.Subtitles,
-					"CLOSED-CAPTIONS":   p.CurrentStreamInf.ClosedCaptions,
-				},
+		p.Insert(&node.Node{
+			HLSElement: &node.HLSElement{
+				Name:        "StreamInf",
+				URI:         line,
+				Attrs:       maps.Clone(p.CurrentStreamInf.Attrs),
+				QuotedAttrs: maps.Clone(p.CurrentStreamInf.QuotedAttrs),
 			},
 		})
 		p.CurrentStreamInf = nil
@@ -145,6 +288,29 @@ func TagsToMap(line string) map[string]string {
 	return m
 }
 
+// Returns, for each attribute in the given line, whether its value is a quoted-string.
+func QuotedAttributes(line string) map[string]bool {
+	m := make(map[string]bool)
+	for _, kv := range ParamRegex.FindAllStringSubmatch(line, -1) {
+		m[strings.ToUpper(kv[1])] = strings.HasPrefix(kv[2], "\"")
+	}
+
+	return m
+}
+
+// Returns a copy of the given node, with its own HLSElement and attribute maps, that is not linked to any list.
+func copyNode(original *node.Node) *node.Node {
+	return &node.Node{
+		HLSElement: &node.HLSElement{
+			Name:        original.HLSElement.Name,
+			URI:         original.HLSElement.URI,
+			Attrs:       maps.Clone(original.HLSElement.Attrs),
+			Details:     maps.Clone(original.HLSElement.Details),
+			QuotedAttrs: maps.Clone(original.HLSElement.QuotedAttrs),
+		},
+	}
+}
+
 // Rounds a float64 value to a specified precision.
 func RoundFloat(val float64, precision uint) float64 {
 	ratio := math.Pow(10, float64(precision))
@@ -153,10 +319,10 @@ func RoundFloat(val float64, precision uint) float64 {
 
 // AUXILIARY METHODS FOR ENCODING
 
-// Encodes a tag with key-value attributes into a string.
-func EncodeTagWithAttributes(builder *strings.Builder, tag string, attrs map[string]string, order []string, shouldQuote map[string]bool) error {
+// Encodes a tag with key-value attributes and writes it to w.
+func EncodeTagWithAttributes(w io.Writer, tag string, attrs map[string]string, order []string, shouldQuote map[string]bool) error {
 	if len(attrs) == 0 {
-		_, err := builder.WriteString(tag + "\n")
+		_, err := io.WriteString(w, tag+"\n")
 		return err
 	}
 
@@ -183,20 +349,23 @@ func EncodeTagWithAttributes(builder *strings.Builder, tag string, attrs map[str
 
 	attributes := fmt.Sprintf("%s:%s\n", tag, strings.Join(formattedAttrs, ","))
 
-	_, err := builder.WriteString(attributes)
+	_, err := io.WriteString(w, attributes)
 	return err
 }
 
-// Encodes a tag without attributes into a string.
-func EncodeSimpleTag(node *internal.Node, builder *strings.Builder, tag, attrKey string) error {
+// Encodes a tag with a single value and writes it to w.
+func EncodeSimpleTag(node *node.Node, w io.Writer, tag, attrKey string) error {
 	if value, exists := node.HLSElement.Attrs[attrKey]; exists {
 		attr := fmt.Sprintf("%s:%s\n", tag, value)
-		_, err := builder.WriteString(attr)
+		_, err := io.WriteString(w, attr)
 		return err
 	}
 	return fmt.Errorf("attribute %s not found for tag %s", attrKey, tag)
 }
 
+// Quoted-string attributes whose value is a tab-separated list, which must not be escaped when quoted.
+var tabSeparatedAttributes = []string{"RECENTLY-REMOVED-DATERANGES"}
+
 // Formats a key-value tag attribute into a string, optionally quoting the value based on the shouldQuote map.
 func FormatAttribute(key, value string, shouldQuote map[string]bool) string {
 	shouldQuoteValue, exists := shouldQuote[key]
@@ -205,9 +374,18 @@ func FormatAttribute(key, value string, shouldQuote map[string]bool) string {
 		shouldQuoteValue = true // default to quoting if not specified
 	}
 
+	if shouldQuoteValue && slices.Contains(tabSeparatedAttributes, key) {
+		return formatUnescapedQuotedAttribute(key, value)
+	}
+
 	if shouldQuoteValue {
 		return fmt.Sprintf(`%s=%q`, key, value)
 	}
 
 	return fmt.Sprintf(`%s=%s`, key, value)
 }
+
+// Formats a key-value tag attribute with its value between double quotes, without escaping it (e.g. tabs).
+func formatUnescapedQuotedAttribute(key, value string) string {
+	return fmt.Sprintf(`%s="%s"`, key, value)
+}
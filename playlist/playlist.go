@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/globocom/go-m3u8/internal"
+	"github.com/globocom/go-m3u8/node"
 	"github.com/rs/zerolog/log"
 )
 
@@ -17,9 +19,11 @@ var (
 )
 
 type Playlist struct {
-	*internal.DoublyLinkedList
+	*node.DoublyLinkedList
 	CurrentSegment        *ExtInfData
 	CurrentStreamInf      *StreamInfData
+	CurrentByteRange      *ByteRangeData
+	CurrentGap            bool
 	ProgramDateTime       time.Time
 	MediaSequence         int
 	DiscontinuitySequence int
@@ -30,9 +34,11 @@ type Playlist struct {
 // Returns new Playlist instance with an empty doubly linked list
 func NewPlaylist() *Playlist {
 	return &Playlist{
-		DoublyLinkedList:      new(internal.DoublyLinkedList),
+		DoublyLinkedList:      new(node.DoublyLinkedList),
 		CurrentSegment:        nil,
 		CurrentStreamInf:      nil,
+		CurrentByteRange:      nil,
+		CurrentGap:            false,
 		ProgramDateTime:       time.Time{},
 		MediaSequence:         0,
 		DiscontinuitySequence: 0,
@@ -41,6 +47,42 @@ func NewPlaylist() *Playlist {
 	}
 }
 
+// Recomputes the parser state fields (MediaSequence, DiscontinuitySequence, ProgramDateTime, SegmentsCounter and DVR)
+// from the nodes in the playlist, the same way they are computed while parsing it.
+func (p *Playlist) syncState() {
+	p.CurrentSegment = nil
+	p.CurrentStreamInf = nil
+	p.CurrentByteRange = nil
+	p.CurrentGap = false
+	p.ProgramDateTime = time.Time{}
+	p.MediaSequence = 0
+	p.DiscontinuitySequence = 0
+	p.SegmentsCounter = 0
+	p.DVR = 0
+
+	for current := p.Head; current != nil; current = current.Next {
+		attrs := current.HLSElement.Attrs
+		switch current.HLSElement.Name {
+		case "MediaSequence":
+			p.MediaSequence, _ = strconv.Atoi(attrs["#EXT-X-MEDIA-SEQUENCE"])
+		case "DiscontinuitySequence":
+			p.DiscontinuitySequence, _ = strconv.Atoi(attrs["#EXT-X-DISCONTINUITY-SEQUENCE"])
+		case "ProgramDateTime":
+			if p.ProgramDateTime.IsZero() {
+				if parsedTime, err := time.Parse(time.RFC3339Nano, attrs["#EXT-X-PROGRAM-DATE-TIME"]); err == nil {
+					p.ProgramDateTime = parsedTime
+				}
+			}
+		case "Skip":
+			skipped, _ := strconv.Atoi(attrs["SKIPPED-SEGMENTS"])
+			p.SegmentsCounter += skipped
+		case "ExtInf":
+			p.DVR = RoundFloat(p.DVR+segmentDuration(current), 4)
+			p.SegmentsCounter++
+		}
+	}
+}
+
 // Prints the playlist to stdout for debugging purposes
 func (p *Playlist) Print() {
 	if p.Head == nil || p.Tail == nil {
@@ -68,7 +110,7 @@ func (p *Playlist) VersionValue() string {
 }
 
 // Returns the Version (#EXT-X-VERSION) tag as a Node if it exists, otherwise returns nil and false
-func (p *Playlist) VersionTag() (*internal.Node, bool) {
+func (p *Playlist) VersionTag() (*node.Node, bool) {
 	return p.Find("Version")
 }
 
@@ -82,7 +124,7 @@ func (p *Playlist) MediaSequenceValue() string {
 }
 
 // Returns the MediaSequence (#EXT-X-MEDIA-SEQUENCE) tag as a Node if it exists, otherwise returns nil and false
-func (p *Playlist) MediaSequenceTag() (*internal.Node, bool) {
+func (p *Playlist) MediaSequenceTag() (*node.Node, bool) {
 	return p.Find("MediaSequence")
 }
 
@@ -96,53 +138,233 @@ func (p *Playlist) DiscontinuitySequenceValue() string {
 }
 
 // Returns the DiscontinuitySequence (#EXT-X-DISCONTINUITY-SEQUENCE) tag as a Node if it exists, otherwise returns nil and false
-func (p *Playlist) DiscontinuitySequenceTag() (*internal.Node, bool) {
+func (p *Playlist) DiscontinuitySequenceTag() (*node.Node, bool) {
 	return p.Find("DiscontinuitySequence")
 }
 
+// Returns the PlaylistType (#EXT-X-PLAYLIST-TYPE) tag's value as a string (i.e. "VOD" or "EVENT")
+func (p *Playlist) PlaylistTypeValue() string {
+	node, found := p.Find("PlaylistType")
+	if !found {
+		return ""
+	}
+	return node.HLSElement.Attrs["#EXT-X-PLAYLIST-TYPE"]
+}
+
+// Returns the PlaylistType (#EXT-X-PLAYLIST-TYPE) tag as a Node if it exists, otherwise returns nil and false
+func (p *Playlist) PlaylistTypeTag() (*node.Node, bool) {
+	return p.Find("PlaylistType")
+}
+
+// Returns the Endlist (#EXT-X-ENDLIST) tag as a Node if it exists, otherwise returns nil and false
+func (p *Playlist) EndlistTag() (*node.Node, bool) {
+	return p.Find("Endlist")
+}
+
+// Returns true if the Media Playlist will not change anymore,
+// i.e. its playlist type is VOD or it has an Endlist (#EXT-X-ENDLIST) tag.
+func (p *Playlist) IsVOD() bool {
+	_, ended := p.EndlistTag()
+	return ended || p.PlaylistTypeValue() == "VOD"
+}
+
+// Returns true if the Media Playlist is an ongoing event,
+// i.e. its playlist type is EVENT and segments can only be appended to it.
+func (p *Playlist) IsEvent() bool {
+	_, ended := p.EndlistTag()
+	return !ended && p.PlaylistTypeValue() == "EVENT"
+}
+
+// Returns true if the Media Playlist is a live sliding window,
+// i.e. it has no playlist type and no Endlist (#EXT-X-ENDLIST) tag, so segments can be removed from it.
+func (p *Playlist) IsLive() bool {
+	_, ended := p.EndlistTag()
+	return !ended && p.PlaylistTypeValue() == ""
+}
+
+// Returns the ServerControl (#EXT-X-SERVER-CONTROL) tag's attributes as a ServerControlData object if it exists, otherwise returns nil and false
+func (p *Playlist) ServerControl() (*ServerControlData, bool) {
+	node, found := p.Find("ServerControl")
+	if !found {
+		return nil, false
+	}
+
+	data, err := GetServerControlData(node.HLSElement.Attrs)
+	if err != nil {
+		log.Warn().Str("service", "go-m3u8/playlist.go").Err(err).Msg("could not parse server control tag")
+		return nil, false
+	}
+	return data, true
+}
+
+// Returns the Start (#EXT-X-START) tag as a Node if it exists, otherwise returns nil and false
+func (p *Playlist) StartTag() (*node.Node, bool) {
+	return p.Find("Start")
+}
+
+// Returns the Start (#EXT-X-START) tag's attributes as a StartData object if it exists, otherwise returns nil and false
+func (p *Playlist) Start() (*StartData, bool) {
+	node, found := p.StartTag()
+	if !found {
+		return nil, false
+	}
+
+	data, err := GetStartData(node.HLSElement.Attrs)
+	if err != nil {
+		log.Warn().Str("service", "go-m3u8/playlist.go").Err(err).Msg("could not parse start tag")
+		return nil, false
+	}
+	return data, true
+}
+
+// Resolves the Start (#EXT-X-START) tag's TIME-OFFSET against the segments durations, and returns the segment (#EXTINF)
+// node where playback starts along with the offset (in seconds) inside it. Returns nil, 0 and false if the playlist has
+// no Start tag or no segments.
+//
+// A positive TIME-OFFSET is measured from the beginning of the playlist, and a negative one from the end of its last segment.
+// An offset that exceeds the playlist duration indicates its end (if positive) or its beginning (if negative).
+// An offset that falls on a segment boundary resolves to the beginning of the following segment.
+//
+// The offset inside the segment is returned regardless of PRECISE, which tells whether clients start presenting at
+// that offset (YES) or at the beginning of the segment (NO).
+func (p *Playlist) StartSegment() (*node.Node, float64, bool) {
+	start, found := p.Start()
+	if !found {
+		return nil, 0, false
+	}
+
+	segments := p.Segments()
+	if len(segments) == 0 {
+		return nil, 0, false
+	}
+
+	total := 0.0
+	for _, segment := range segments {
+		total += segmentDuration(segment)
+	}
+
+	position := start.TimeOffset
+	if position < 0 {
+		position += total
+	}
+	position = RoundFloat(max(0, min(position, total)), 4)
+
+	elapsed := 0.0
+	for _, segment := range segments {
+		end := RoundFloat(elapsed+segmentDuration(segment), 4)
+		if position < end {
+			return segment, RoundFloat(position-elapsed, 4), true
+		}
+		elapsed = end
+	}
+
+	// the offset indicates the end of the playlist
+	last := segments[len(segments)-1]
+	return last, segmentDuration(last), true
+}
+
 // Returns the VariableDefine (#EXT-X-DEFINE) tag as a Node if it exists, otherwise returns nil and false
-func (p *Playlist) VariableDefineTag() (*internal.Node, bool) {
+func (p *Playlist) VariableDefineTag() (*node.Node, bool) {
 	return p.Find("VariableDefine")
 }
 
 // Returns all StreamInf (#EXT-X-STREAM-INF) nodes in the playlist
-func (p *Playlist) Variants() []*internal.Node {
+func (p *Playlist) Variants() []*node.Node {
 	return p.FindAll("StreamInf")
 }
 
 // Returns all Media (#EXT-X-MEDIA) nodes in the playlist (i.e. AUDIO groups, CLOSED-CAPTIONS groups, etc.)
-func (p *Playlist) MediaGroups() []*internal.Node {
+func (p *Playlist) MediaGroups() []*node.Node {
 	return p.FindAll("Media")
 }
 
 // Returns all IFrameStreamInf (#EXT-X-I-FRAME-STREAM-INF) nodes in the playlist (i.e. keyframes)
-func (p *Playlist) Keyframes() []*internal.Node {
+func (p *Playlist) Keyframes() []*node.Node {
 	return p.FindAll("IFrameStreamInf")
 }
 
 // Returns all ExtInf (#EXTINF) nodes in the playlist
-func (p *Playlist) Segments() []*internal.Node {
+func (p *Playlist) Segments() []*node.Node {
 	return p.FindAll("ExtInf")
 }
 
+// Returns all segment (#EXTINF) nodes in the playlist that are not marked with the Gap (#EXT-X-GAP) tag,
+// i.e. the segments whose media is actually available.
+func (p *Playlist) AvailableSegments() []*node.Node {
+	return slices.DeleteFunc(p.Segments(), p.IsGap)
+}
+
+// Returns all segment (#EXTINF) nodes in the playlist that are marked with the Gap (#EXT-X-GAP) tag,
+// i.e. the segments whose media is missing and that clients must not load.
+func (p *Playlist) GapSegments() []*node.Node {
+	return slices.DeleteFunc(p.Segments(), func(node *node.Node) bool { return !p.IsGap(node) })
+}
+
+// Returns true if the given segment (#EXTINF) node is marked with the Gap (#EXT-X-GAP) tag.
+func (p *Playlist) IsGap(node *node.Node) bool {
+	return node.HLSElement.Name == "ExtInf" && node.HLSElement.Attrs["Gap"] == "YES"
+}
+
+// Returns all Part (#EXT-X-PART) nodes that belong to the given segment (#EXTINF), in playlist order.
+// Partial Segments are listed before their Parent Segment, so these are the Part nodes between the given segment and the previous one.
+// When segment is nil, returns the Part nodes after the last segment (i.e. the Parent Segment still being produced).
+func (p *Playlist) Parts(segment *node.Node) []*node.Node {
+	result := make([]*node.Node, 0)
+
+	current := p.Tail
+	if segment != nil {
+		current = segment.Prev
+	}
+
+	for current != nil && current.HLSElement.Name != "ExtInf" {
+		if current.HLSElement.Name == "Part" {
+			result = append(result, current)
+		}
+		current = current.Prev
+	}
+
+	slices.Reverse(result)
+	return result
+}
+
+// Returns the PreloadHint (#EXT-X-PRELOAD-HINT) nodes in the playlist
+func (p *Playlist) PreloadHints() []*node.Node {
+	return p.FindAll("PreloadHint")
+}
+
+// Returns the resolved byte range of the given segment (#EXTINF) or Map (#EXT-X-MAP) node as a ByteRangeData object.
+// Returns nil and false if the node is not a sub-range of its resource.
+func (p *Playlist) ByteRange(node *node.Node) (*ByteRangeData, bool) {
+	length, lengthExists := node.HLSElement.Details["ByteRangeLength"]
+	offset, offsetExists := node.HLSElement.Details["ByteRangeOffset"]
+	if !lengthExists || !offsetExists {
+		return nil, false
+	}
+
+	byteRange := &ByteRangeData{HasOffset: true}
+	byteRange.Length, _ = strconv.ParseInt(length, 10, 64)
+	byteRange.Offset, _ = strconv.ParseInt(offset, 10, 64)
+	return byteRange, true
+}
+
 // Returns all Key (#EXT-X-KEY) nodes in the playlist
-func (p *Playlist) EncryptionTags() []*internal.Node {
+func (p *Playlist) EncryptionTags() []*node.Node {
 	return p.FindAll("Key")
 }
 
 // Returns all CueOut (#EXT-X-CUE-OUT) nodes in the playlist
-func (p *Playlist) CueOutEvents() []*internal.Node {
+func (p *Playlist) CueOutEvents() []*node.Node {
 	return p.FindAll("CueOut")
 }
 
 // Returns all CueIn (#EXT-X-CUE-IN) nodes in the playlist
-func (p *Playlist) CueInEvents() []*internal.Node {
+func (p *Playlist) CueInEvents() []*node.Node {
 	return p.FindAll("CueIn")
 }
 
 // Returns all DateRange (#EXT-X-DATERANGE) nodes with SCTE35-OUT marking in the playlist
-func (p *Playlist) Breaks() []*internal.Node {
-	result := make([]*internal.Node, 0)
+func (p *Playlist) Breaks() []*node.Node {
+	result := make([]*node.Node, 0)
 	nodes := p.FindAll("DateRange")
 	for _, node := range nodes {
 		if node.HLSElement.Attrs["SCTE35-OUT"] != "" {
@@ -153,8 +375,8 @@ func (p *Playlist) Breaks() []*internal.Node {
 }
 
 // Returns all DateRange (#EXT-X-DATERANGE) nodes with SCTE35-IN marking in the playlist
-func (p *Playlist) SCTE35InTags() []*internal.Node {
-	result := make([]*internal.Node, 0)
+func (p *Playlist) SCTE35InTags() []*node.Node {
+	result := make([]*node.Node, 0)
 	nodes := p.FindAll("DateRange")
 	for _, node := range nodes {
 		if node.HLSElement.Attrs["SCTE35-IN"] != "" {
@@ -164,10 +386,15 @@ func (p *Playlist) SCTE35InTags() []*internal.Node {
 	return result
 }
 
+// Returns all UnknownTag nodes in the playlist, i.e. the tags without a registered parser (e.g. vendor tags)
+func (p *Playlist) UnknownTags() []*node.Node {
+	return p.FindAll("UnknownTag")
+}
+
 // Returns the first Comment node in the playlist whose value contains the given matchString.
 //
 //	Example: "# variants", "# AUDIO groups", etc
-func (p *Playlist) Comment(matchString string) *internal.Node {
+func (p *Playlist) Comment(matchString string) *node.Node {
 	nodes := p.FindAll("Comment")
 	for _, node := range nodes {
 		if strings.Contains(node.HLSElement.Attrs["Comment"], matchString) {
@@ -186,7 +413,7 @@ func (p *Playlist) Comment(matchString string) *internal.Node {
 //   - DateRange SCTE-IN is ALWAYS present.
 //   - No DateRange SCTE-IN. Exit is ONLY marked by CueIn (#EXT-X-CUE-IN) tag instead.
 //   - SOMETIMES DateRange SCTE-IN is present, alongside the CueIn tag.
-func (p *Playlist) FindNodeInsideAdBreak(node *internal.Node) (*internal.Node, bool) {
+func (p *Playlist) FindNodeInsideAdBreak(node *node.Node) (*node.Node, bool) {
 	current := node.Prev
 	for current != nil {
 		// node is inside Ad Break if it is preceded by a DateRange tag with attribute SCTE35-OUT
@@ -206,7 +433,7 @@ func (p *Playlist) FindNodeInsideAdBreak(node *internal.Node) (*internal.Node, b
 }
 
 // Returns the previous segment (#EXTINF) before the given node, or nil if none exists.
-func (p *Playlist) FindPreviousSegment(node *internal.Node) *internal.Node {
+func (p *Playlist) FindPreviousSegment(node *node.Node) *node.Node {
 	current := node.Prev
 	for current != nil {
 		if current.HLSElement.Name == "ExtInf" {
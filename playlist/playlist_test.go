@@ -6,6 +6,7 @@ import (
 
 	m3u8 "github.com/globocom/go-m3u8"
 	"github.com/globocom/go-m3u8/internal"
+	pl "github.com/globocom/go-m3u8/playlist"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -69,6 +70,81 @@ func TestDiscontinuitySequenceTag(t *testing.T) {
 	assert.Equal(t, node.HLSElement.Attrs["#EXT-X-DISCONTINUITY-SEQUENCE"], "87498")
 }
 
+func TestPlaylistTypeValue(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/withPlaylistTypeVOD.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	assert.Equal(t, playlist.PlaylistTypeValue(), "VOD")
+
+	node, found := playlist.PlaylistTypeTag()
+	assert.True(t, found)
+	assert.Equal(t, node.HLSElement.Attrs["#EXT-X-PLAYLIST-TYPE"], "VOD")
+}
+
+func TestPlaylistKind(t *testing.T) {
+	// VOD playlist
+	file, _ := os.Open("./../mocks/media/withPlaylistTypeVOD.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	assert.True(t, playlist.IsVOD())
+	assert.False(t, playlist.IsEvent())
+	assert.False(t, playlist.IsLive())
+
+	// ongoing EVENT playlist
+	file, _ = os.Open("./../mocks/media/withPlaylistTypeEvent.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	assert.False(t, playlist.IsVOD())
+	assert.True(t, playlist.IsEvent())
+	assert.False(t, playlist.IsLive())
+
+	// live playlist that has ended
+	file, _ = os.Open("./../mocks/media/withEndlist.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	_, found := playlist.EndlistTag()
+	assert.True(t, found)
+	assert.True(t, playlist.IsVOD())
+	assert.False(t, playlist.IsEvent())
+	assert.False(t, playlist.IsLive())
+
+	// live sliding window playlist
+	file, _ = os.Open("./../mocks/media/media.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	assert.False(t, playlist.IsVOD())
+	assert.False(t, playlist.IsEvent())
+	assert.True(t, playlist.IsLive())
+}
+
+func TestServerControl(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/lowlatency/withParts.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	serverControl, found := playlist.ServerControl()
+	assert.True(t, found)
+	assert.True(t, serverControl.CanBlockReload)
+	assert.False(t, serverControl.CanSkipDateRanges)
+	assert.Equal(t, serverControl.CanSkipUntil, 24.0)
+	assert.Equal(t, serverControl.PartHoldBack, 3.012)
+	assert.Equal(t, serverControl.HoldBack, 0.0)
+
+	// playlist without server control tag
+	file, _ = os.Open("./../mocks/media/media.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	serverControl, found = playlist.ServerControl()
+	assert.False(t, found)
+	assert.Nil(t, serverControl)
+}
+
 func TestVariableDefineTag(t *testing.T) {
 	file, _ := os.Open("./../mocks/multivariant/withQueryParam.m3u8")
 	playlist, err := m3u8.ParsePlaylist(file)
@@ -122,6 +198,179 @@ func TestSegments(t *testing.T) {
 	assert.Len(t, nodes, 27)
 }
 
+func TestParts(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/lowlatency/withParts.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	segments := playlist.Segments()
+
+	// segment without partial segments
+	assert.Empty(t, playlist.Parts(segments[0]))
+
+	// #EXTINF:4.00008,
+	// fileSequence271.mp4
+	parts := playlist.Parts(segments[5])
+	assert.Len(t, parts, 4)
+	assert.Equal(t, parts[0].HLSElement.Attrs["URI"], "filePart271.0.mp4")
+	assert.Equal(t, parts[1].HLSElement.Attrs["GAP"], "YES")
+	assert.Equal(t, parts[3].HLSElement.Attrs["URI"], "filePart271.3.mp4")
+
+	// parts of the segment still being produced
+	parts = playlist.Parts(nil)
+	assert.Len(t, parts, 2)
+	assert.Equal(t, parts[0].HLSElement.Attrs["URI"], "filePart272.a.mp4")
+	assert.Equal(t, parts[1].HLSElement.Attrs["BYTERANGE"], "20000@0")
+
+	hints := playlist.PreloadHints()
+	assert.Len(t, hints, 1)
+	assert.Equal(t, hints[0].HLSElement.Attrs["URI"], "filePart272.c.mp4")
+}
+
+func TestByteRange(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/withByteRange.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	expected := []pl.ByteRangeData{
+		{Length: 1498352, Offset: 719, HasOffset: true},
+		{Length: 1327488, Offset: 1499071, HasOffset: true},
+		{Length: 1455620, Offset: 2826559, HasOffset: true},
+		{Length: 803112, Offset: 4282179, HasOffset: true},
+	}
+	for i, segment := range playlist.Segments() {
+		byteRange, found := playlist.ByteRange(segment)
+		assert.True(t, found)
+		assert.Equal(t, expected[i], *byteRange)
+	}
+
+	mapNode, _ := playlist.Find("Map")
+	byteRange, found := playlist.ByteRange(mapNode)
+	assert.True(t, found)
+	assert.Equal(t, pl.ByteRangeData{Length: 719, Offset: 0, HasOffset: true}, *byteRange)
+
+	// segments that are not sub-ranges
+	file, _ = os.Open("./../mocks/media/media.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	byteRange, found = playlist.ByteRange(playlist.Segments()[0])
+	assert.False(t, found)
+	assert.Nil(t, byteRange)
+}
+
+func TestGapSegments(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/withGap.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	segments := playlist.Segments()
+	assert.Len(t, segments, 4)
+	assert.False(t, playlist.IsGap(segments[0]))
+	assert.True(t, playlist.IsGap(segments[1]))
+	assert.True(t, playlist.IsGap(segments[2]))
+	assert.False(t, playlist.IsGap(segments[3]))
+
+	gaps := playlist.GapSegments()
+	assert.Len(t, gaps, 2)
+	assert.Equal(t, "364856602", gaps[0].HLSElement.Details["MediaSequence"])
+	assert.Equal(t, "364856603", gaps[1].HLSElement.Details["MediaSequence"])
+
+	available := playlist.AvailableSegments()
+	assert.Len(t, available, 2)
+	assert.Equal(t, "364856601", available[0].HLSElement.Details["MediaSequence"])
+	assert.Equal(t, "364856604", available[1].HLSElement.Details["MediaSequence"])
+
+	// gap segments keep counting towards the playlist timeline
+	assert.Equal(t, 19.2, playlist.DVR)
+	assert.Equal(t, "2025-06-30T19:28:14.5Z", available[1].HLSElement.Details["ProgramDateTime"])
+
+	// playlist without gaps
+	file, _ = os.Open("./../mocks/media/media.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+	assert.Empty(t, playlist.GapSegments())
+	assert.Equal(t, playlist.Segments(), playlist.AvailableSegments())
+}
+
+func TestStart(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/withStart.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	start, found := playlist.Start()
+	assert.True(t, found)
+	assert.Equal(t, pl.StartData{TimeOffset: -6, Precise: true}, *start)
+
+	// playlist without start tag
+	file, _ = os.Open("./../mocks/media/media.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	start, found = playlist.Start()
+	assert.False(t, found)
+	assert.Nil(t, start)
+
+	segment, offset, found := playlist.StartSegment()
+	assert.False(t, found)
+	assert.Nil(t, segment)
+	assert.Equal(t, 0.0, offset)
+}
+
+func TestStartSegment(t *testing.T) {
+	// segments durations are 4.8, 4.8, 4.8 and 3.2 (17.6 seconds)
+	tests := []struct {
+		name           string
+		timeOffset     string
+		expectedURI    string
+		expectedOffset float64
+	}{
+		{"negative offset from the end", "-6.0", "channel-audio_1=96000-video=789952-364856603.ts", 2},
+		{"negative offset on a segment boundary", "-8", "channel-audio_1=96000-video=789952-364856603.ts", 0},
+		{"negative offset exceeding the playlist duration", "-30", "channel-audio_1=96000-video=789952-364856601.ts", 0},
+		{"zero offset", "0", "channel-audio_1=96000-video=789952-364856601.ts", 0},
+		{"positive offset from the beginning", "10", "channel-audio_1=96000-video=789952-364856603.ts", 0.4},
+		{"positive offset on a segment boundary", "4.8", "channel-audio_1=96000-video=789952-364856602.ts", 0},
+		{"positive offset exceeding the playlist duration", "30", "channel-audio_1=96000-video=789952-364856604.ts", 3.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, _ := os.Open("./../mocks/media/withStart.m3u8")
+			playlist, err := m3u8.ParsePlaylist(file)
+			assert.NoError(t, err)
+
+			startNode, _ := playlist.StartTag()
+			startNode.HLSElement.Attrs["TIME-OFFSET"] = tt.timeOffset
+
+			segment, offset, found := playlist.StartSegment()
+			assert.True(t, found)
+			assert.Equal(t, tt.expectedURI, segment.HLSElement.URI)
+			assert.Equal(t, tt.expectedOffset, offset)
+		})
+	}
+}
+
+func TestVariantsKeepAllAttributes(t *testing.T) {
+	file, _ := os.Open("./../mocks/multivariant/withAllStreamInfAttributes.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	variants := playlist.Variants()
+	assert.Len(t, variants, 2)
+	assert.Equal(t, "dvh1.08.07/db4h", variants[0].HLSElement.Attrs["SUPPLEMENTAL-CODECS"])
+	assert.Equal(t, "2.5", variants[0].HLSElement.Attrs["SCORE"])
+	assert.Equal(t, "uhd", variants[0].HLSElement.Attrs["X-VENDOR-LABEL"])
+	assert.Equal(t, "1", variants[1].HLSElement.Attrs["PROGRAM-ID"])
+
+	// every attribute survives a parse and encode round trip
+	manifest, err := m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+
+	expected, _ := os.ReadFile("./../mocks/multivariant/withAllStreamInfAttributes.m3u8")
+	assert.Equal(t, string(expected), manifest)
+}
+
 func TestEncryptionTags(t *testing.T) {
 	file, _ := os.Open("./../mocks/media/encryption/withAES128.m3u8")
 	playlist, err := m3u8.ParsePlaylist(file)
@@ -0,0 +1,167 @@
+package playlist
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/globocom/go-m3u8/node"
+)
+
+// METHODS FOR RENDITION REPORTS
+
+// Returns all RenditionReport (#EXT-X-RENDITION-REPORT) nodes in the playlist
+func (p *Playlist) RenditionReports() []*node.Node {
+	return p.FindAll("RenditionReport")
+}
+
+// Returns the Media Sequence Number of the last segment in the Media Playlist, and the index of its last Partial Segment.
+// When the playlist has Partial Segments after its last segment, these belong to the Parent Segment still being produced,
+// so its Media Sequence Number is returned instead. The returned part is -1 when the segment has no Partial Segments.
+func (p *Playlist) LastMediaSequence() (lastMSN, lastPart int) {
+	lastPart = -1
+
+	parts := p.Parts(nil)
+	if len(parts) > 0 {
+		lastMSN, _ = strconv.Atoi(parts[len(parts)-1].HLSElement.Details["MediaSequence"])
+		lastPart, _ = strconv.Atoi(parts[len(parts)-1].HLSElement.Details["PartIndex"])
+		return lastMSN, lastPart
+	}
+
+	segments := p.Segments()
+	if len(segments) == 0 {
+		return p.MediaSequence + p.SegmentsCounter - 1, lastPart
+	}
+
+	lastSegment := segments[len(segments)-1]
+	lastMSN, _ = strconv.Atoi(lastSegment.HLSElement.Details["MediaSequence"])
+	if parts = p.Parts(lastSegment); len(parts) > 0 {
+		lastPart, _ = strconv.Atoi(parts[len(parts)-1].HLSElement.Details["PartIndex"])
+	}
+	return lastMSN, lastPart
+}
+
+// Adds Rendition Report (#EXT-X-RENDITION-REPORT) tags to the given Media Playlists of the Multivariant Playlist.
+//
+// The renditions map holds each Media Playlist by its URI, as listed in the Multivariant Playlist.
+// Each Media Playlist receives a report for every other given rendition, in the Multivariant Playlist order.
+// Existing reports for the same URI are updated, and new ones are appended to the end of the Media Playlist.
+//
+// Report URIs are made relative to the Media Playlist that holds them (e.g. audio/en.m3u8 is reported as ../audio/en.m3u8
+// in video/720p.m3u8), as the RFC requires. Returns an error if a relative URI cannot be resolved against the one
+// of a Media Playlist, which happens when that one is absolute (or starts with "/") and the other one is not.
+// In that case, none of the Media Playlists is changed.
+func (p *Playlist) AddRenditionReports(renditions map[string]*Playlist) error {
+	uris := make([]string, 0, len(renditions))
+	for _, variant := range p.Variants() {
+		uris = append(uris, variant.HLSElement.URI)
+	}
+	for _, media := range p.MediaGroups() {
+		uris = append(uris, media.HLSElement.Attrs["URI"])
+	}
+
+	reports := make(map[string]map[string]string, len(renditions))
+	ordered := make([]string, 0, len(renditions))
+	for _, uri := range uris {
+		rendition, exists := renditions[uri]
+		if !exists || slices.Contains(ordered, uri) {
+			continue
+		}
+
+		lastMSN, lastPart := rendition.LastMediaSequence()
+		attrs := map[string]string{
+			"LAST-MSN": strconv.Itoa(lastMSN),
+		}
+		if lastPart >= 0 {
+			attrs["LAST-PART"] = strconv.Itoa(lastPart)
+		}
+
+		reports[uri] = attrs
+		ordered = append(ordered, uri)
+	}
+
+	// every URI is resolved before any report is added, so no playlist is changed when one of them fails
+	type renditionReport struct {
+		rendition *Playlist
+		attrs     map[string]string
+	}
+	resolved := make([]renditionReport, 0, len(ordered)*len(ordered))
+	for _, uri := range ordered {
+		for _, reportURI := range ordered {
+			if reportURI == uri {
+				continue
+			}
+
+			relativeURI, err := relativeReference(uri, reportURI)
+			if err != nil {
+				return err
+			}
+			attrs := maps.Clone(reports[reportURI])
+			attrs["URI"] = relativeURI
+			resolved = append(resolved, renditionReport{rendition: renditions[uri], attrs: attrs})
+		}
+	}
+
+	for _, report := range resolved {
+		report.rendition.upsertRenditionReport(report.attrs)
+	}
+	return nil
+}
+
+// Updates the RenditionReport node with the same URI as the given attributes, or appends a new one to the playlist.
+func (p *Playlist) upsertRenditionReport(attrs map[string]string) {
+	for _, report := range p.RenditionReports() {
+		if report.HLSElement.Attrs["URI"] == attrs["URI"] {
+			report.HLSElement.Attrs = attrs
+			return
+		}
+	}
+	p.Insert(p.NewNode("RenditionReport", "", attrs, nil))
+}
+
+// Returns the reference to the target URI from the playlist at the given URI, both relative to the same playlist.
+// Absolute URIs and absolute paths are returned as they are, unless they share the scheme and host of uri.
+func relativeReference(uri, target string) (string, error) {
+	from, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid rendition URI %s: %w", uri, err)
+	}
+	to, err := url.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid rendition URI %s: %w", target, err)
+	}
+
+	toAbsolute := to.Host != "" || to.Scheme != ""
+	fromAbsolute := from.Host != "" || from.Scheme != ""
+	switch {
+	case toAbsolute && (from.Scheme != to.Scheme || from.Host != to.Host):
+		return target, nil
+	case !toAbsolute && strings.HasPrefix(to.Path, "/"):
+		return target, nil
+	case !toAbsolute && (fromAbsolute || strings.HasPrefix(from.Path, "/")):
+		return "", fmt.Errorf("relative rendition URI %s cannot be reported in %s", target, uri)
+	}
+
+	root := &url.URL{Path: "/"}
+	fromDir := strings.Trim(path.Dir(root.ResolveReference(from).Path), "/")
+	toPath := strings.TrimPrefix(root.ResolveReference(to).Path, "/")
+
+	fromParts := make([]string, 0)
+	if fromDir != "" {
+		fromParts = strings.Split(fromDir, "/")
+	}
+	toParts := strings.Split(toPath, "/")
+
+	common := 0
+	for common < len(fromParts) && common < len(toParts)-1 && fromParts[common] == toParts[common] {
+		common++
+	}
+
+	relative := strings.Repeat("../", len(fromParts)-common) + strings.Join(toParts[common:], "/")
+	result := &url.URL{Path: relative, RawQuery: to.RawQuery, Fragment: to.Fragment}
+	return result.String(), nil
+}
@@ -0,0 +1,131 @@
+package playlist_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	pl "github.com/globocom/go-m3u8/playlist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLastMediaSequence(t *testing.T) {
+	// partial segments after the last segment
+	file, _ := os.Open("./../mocks/media/lowlatency/withParts.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	lastMSN, lastPart := playlist.LastMediaSequence()
+	assert.Equal(t, 272, lastMSN)
+	assert.Equal(t, 1, lastPart)
+
+	// no partial segments
+	file, _ = os.Open("./../mocks/media/media.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	lastMSN, lastPart = playlist.LastMediaSequence()
+	assert.Equal(t, 364042195, lastMSN)
+	assert.Equal(t, -1, lastPart)
+}
+
+func TestAddRenditionReports(t *testing.T) {
+	file, _ := os.Open("./../mocks/multivariant/withLowLatencyRenditions.m3u8")
+	multivariant, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	renditions := make(map[string]*pl.Playlist)
+	mocks := map[string]string{
+		"video-720p.m3u8":  "./../mocks/media/lowlatency/withParts.m3u8",
+		"video-1080p.m3u8": "./../mocks/media/lowlatency/withDeltaUpdateSupport.m3u8",
+		"audio.m3u8":       "./../mocks/media/media.m3u8",
+	}
+	for uri, path := range mocks {
+		file, _ = os.Open(path)
+		renditions[uri], err = m3u8.ParsePlaylist(file)
+		assert.NoError(t, err)
+	}
+
+	err = multivariant.AddRenditionReports(renditions)
+	assert.NoError(t, err)
+
+	reports := renditions["video-720p.m3u8"].RenditionReports()
+	assert.Len(t, reports, 2)
+	assert.Equal(t, "video-1080p.m3u8", reports[0].HLSElement.Attrs["URI"])
+	assert.Equal(t, "273", reports[0].HLSElement.Attrs["LAST-MSN"])
+	assert.Equal(t, "0", reports[0].HLSElement.Attrs["LAST-PART"])
+	assert.Equal(t, "audio.m3u8", reports[1].HLSElement.Attrs["URI"])
+	assert.Equal(t, "364042195", reports[1].HLSElement.Attrs["LAST-MSN"])
+	assert.Empty(t, reports[1].HLSElement.Attrs["LAST-PART"])
+
+	reports = renditions["audio.m3u8"].RenditionReports()
+	assert.Len(t, reports, 2)
+	assert.Equal(t, "video-720p.m3u8", reports[0].HLSElement.Attrs["URI"])
+	assert.Equal(t, "272", reports[0].HLSElement.Attrs["LAST-MSN"])
+	assert.Equal(t, "1", reports[0].HLSElement.Attrs["LAST-PART"])
+
+	// reports are updated in place when computed again
+	parts := renditions["video-720p.m3u8"].Parts(nil)
+	renditions["video-720p.m3u8"].Remove(parts[len(parts)-1])
+	err = multivariant.AddRenditionReports(renditions)
+	assert.NoError(t, err)
+
+	reports = renditions["audio.m3u8"].RenditionReports()
+	assert.Len(t, reports, 2)
+	assert.Equal(t, renditions["audio.m3u8"].Tail, reports[1])
+
+	encoded, err := m3u8.EncodePlaylist(renditions["audio.m3u8"])
+	assert.NoError(t, err)
+	assert.Contains(t, encoded, `#EXT-X-RENDITION-REPORT:URI="video-720p.m3u8",LAST-MSN=272,LAST-PART=0`+"\n")
+	assert.NotContains(t, encoded, "LAST-PART=1")
+}
+
+func TestAddRenditionReports_RelativeURIs(t *testing.T) {
+	manifest := `#EXTM3U
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="audio",NAME="English",URI="audio/en/audio.m3u8?token=abc"
+#EXT-X-STREAM-INF:BANDWIDTH=1835000,AUDIO="audio"
+video/720p.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=4127000,AUDIO="audio"
+video/1080p.m3u8`
+
+	multivariant, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+
+	renditions := make(map[string]*pl.Playlist)
+	for _, uri := range []string{"video/720p.m3u8", "video/1080p.m3u8", "audio/en/audio.m3u8?token=abc"} {
+		file, _ := os.Open("./../mocks/media/media.m3u8")
+		renditions[uri], err = m3u8.ParsePlaylist(file)
+		assert.NoError(t, err)
+	}
+
+	err = multivariant.AddRenditionReports(renditions)
+	assert.NoError(t, err)
+
+	reportURIs := func(uri string) []string {
+		result := make([]string, 0)
+		for _, report := range renditions[uri].RenditionReports() {
+			result = append(result, report.HLSElement.Attrs["URI"])
+		}
+		return result
+	}
+	assert.Equal(t, []string{"1080p.m3u8", "../audio/en/audio.m3u8?token=abc"}, reportURIs("video/720p.m3u8"))
+	assert.Equal(t, []string{"../../video/720p.m3u8", "../../video/1080p.m3u8"}, reportURIs("audio/en/audio.m3u8?token=abc"))
+
+	// absolute URIs are reported as they are, but relative ones cannot be resolved against them
+	manifest = `#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=1835000
+video/720p.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=4127000
+https://cdn.example.com/video/1080p.m3u8`
+
+	multivariant, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+
+	renditions["https://cdn.example.com/video/1080p.m3u8"] = renditions["video/1080p.m3u8"]
+	err = multivariant.AddRenditionReports(renditions)
+	assert.ErrorContains(t, err, "relative rendition URI video/720p.m3u8 cannot be reported in https://cdn.example.com/video/1080p.m3u8")
+	// no report is added when one of them fails
+	assert.Equal(t, []string{"1080p.m3u8", "../audio/en/audio.m3u8?token=abc"}, reportURIs("video/720p.m3u8"))
+}
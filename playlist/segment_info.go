@@ -0,0 +1,172 @@
+package playlist
+
+import (
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/globocom/go-m3u8/node"
+)
+
+// METHODS FOR RESOLVING SEGMENT STATE
+
+// SegmentInfo holds the state that applies to a segment (#EXTINF) node but is carried by the tags before it:
+//   - Keys: The Key (#EXT-X-KEY) nodes in effect, one per KEYFORMAT. Empty when the segment is not encrypted.
+//   - Map: The Map (#EXT-X-MAP) node that holds the segment's Media Initialization Section, or nil if none.
+//   - Discontinuity: True if the segment is preceded by a Discontinuity (#EXT-X-DISCONTINUITY) tag.
+//   - DiscontinuitySequence: The Discontinuity Sequence Number of the segment.
+//   - ProgramDateTime: The date and time of the segment's first sample, as computed when parsing (i.e. its
+//     ProgramDateTime Details), or the zero time if the playlist has no ProgramDateTime (#EXT-X-PROGRAM-DATE-TIME)
+//     tag up to the segment.
+//   - ByteRange: The resolved sub-range of the segment's resource, or nil if it is not a sub-range.
+//   - DateRanges: The DateRange (#EXT-X-DATERANGE) nodes whose interval overlaps the segment, in playlist order.
+type SegmentInfo struct {
+	Node                  *node.Node
+	Keys                  []*node.Node
+	Map                   *node.Node
+	Discontinuity         bool
+	DiscontinuitySequence int
+	ProgramDateTime       time.Time
+	ByteRange             *ByteRangeData
+	DateRanges            []*node.Node
+}
+
+// Maximum drift between the ProgramDateTime of a segment and the DateRange (#EXT-X-DATERANGE) tags that start or end with it.
+const dateRangeTolerance = 20 * time.Millisecond
+
+// Interval of a DateRange (#EXT-X-DATERANGE) node, ending at start when the node has no duration.
+type dateRangeInterval struct {
+	node       *node.Node
+	start, end time.Time
+}
+
+// Returns the SegmentInfo of the given segment (#EXTINF) node, resolved from the tags before it.
+// Returns nil and false if the node is not a segment of the playlist.
+//
+// Each call walks the playlist up to the node and parses all DateRange (#EXT-X-DATERANGE) tags, so resolving every
+// segment this way takes quadratic time: use SegmentInfos instead.
+func (p *Playlist) SegmentInfo(node *node.Node) (*SegmentInfo, bool) {
+	for info := range p.SegmentInfos() {
+		if info.Node == node {
+			return info, true
+		}
+	}
+	return nil, false
+}
+
+// Returns an iterator over the SegmentInfo of every segment (#EXTINF) node in the playlist, in playlist order.
+// The playlist is walked only once, so it is preferred over calling SegmentInfo for each segment.
+//
+// A Key tag replaces the one in effect with the same KEYFORMAT, and one with METHOD=NONE ends all of them.
+func (p *Playlist) SegmentInfos() iter.Seq[*SegmentInfo] {
+	return func(yield func(*SegmentInfo) bool) {
+		dateRanges := p.dateRangeIntervals()
+
+		keys := make([]*node.Node, 0)
+		var initSection *node.Node
+		discontinuity := false
+		discontinuitySequence := 0
+		hasProgramDateTime := false
+
+		for current := p.Head; current != nil; current = current.Next {
+			attrs := current.HLSElement.Attrs
+			switch current.HLSElement.Name {
+			case "DiscontinuitySequence":
+				discontinuitySequence, _ = strconv.Atoi(attrs["#EXT-X-DISCONTINUITY-SEQUENCE"])
+			case "Discontinuity":
+				discontinuity = true
+			case "ProgramDateTime":
+				hasProgramDateTime = true
+			case "Key":
+				keys = activeKeys(keys, current)
+			case "Map":
+				initSection = current
+			case "ExtInf":
+				if discontinuity {
+					discontinuitySequence++
+				}
+
+				// segments before the first ProgramDateTime tag have no date, even if their Details have one
+				var programDateTime time.Time
+				if hasProgramDateTime {
+					programDateTime, _ = time.Parse(time.RFC3339Nano, current.HLSElement.Details["ProgramDateTime"])
+				}
+
+				info := &SegmentInfo{
+					Node:                  current,
+					Keys:                  keys,
+					Map:                   initSection,
+					Discontinuity:         discontinuity,
+					DiscontinuitySequence: discontinuitySequence,
+					ProgramDateTime:       programDateTime,
+					DateRanges:            coveringDateRanges(dateRanges, programDateTime, segmentDuration(current)),
+				}
+				info.ByteRange, _ = p.ByteRange(current)
+				if !yield(info) {
+					return
+				}
+
+				discontinuity = false
+			}
+		}
+	}
+}
+
+// Returns a new slice with the Key nodes in effect after the given Key node.
+func activeKeys(keys []*node.Node, key *node.Node) []*node.Node {
+	if key.HLSElement.Attrs["METHOD"] == "NONE" {
+		return make([]*node.Node, 0)
+	}
+
+	result := make([]*node.Node, 0, len(keys)+1)
+	for _, current := range keys {
+		if current.HLSElement.Attrs["KEYFORMAT"] != key.HLSElement.Attrs["KEYFORMAT"] {
+			result = append(result, current)
+		}
+	}
+	return append(result, key)
+}
+
+// Returns the intervals of the DateRange (#EXT-X-DATERANGE) nodes in the playlist that have a valid START-DATE.
+// The interval ends at END-DATE, or after DURATION or PLANNED-DURATION (in this order of preference).
+func (p *Playlist) dateRangeIntervals() []dateRangeInterval {
+	result := make([]dateRangeInterval, 0)
+	for _, node := range p.FindAll("DateRange") {
+		attrs := node.HLSElement.Attrs
+		start, err := time.Parse(time.RFC3339Nano, attrs["START-DATE"])
+		if err != nil {
+			continue
+		}
+
+		interval := dateRangeInterval{node: node, start: start, end: start}
+		if end, err := time.Parse(time.RFC3339Nano, attrs["END-DATE"]); err == nil {
+			interval.end = end
+		} else if duration, err := strconv.ParseFloat(attrs["DURATION"], 64); err == nil {
+			interval.end = start.Add(time.Duration(duration * float64(time.Second)))
+		} else if duration, err := strconv.ParseFloat(attrs["PLANNED-DURATION"], 64); err == nil {
+			interval.end = start.Add(time.Duration(duration * float64(time.Second)))
+		}
+		result = append(result, interval)
+	}
+	return result
+}
+
+// Returns the DateRange nodes whose interval overlaps the segment that starts at programDateTime and lasts duration seconds.
+// A DateRange without duration overlaps the segment when it starts inside it. Overlaps up to dateRangeTolerance are
+// ignored, since the ProgramDateTime of the segments drifts from the one of the tags with the rounding of their durations.
+func coveringDateRanges(dateRanges []dateRangeInterval, programDateTime time.Time, duration float64) []*node.Node {
+	result := make([]*node.Node, 0)
+	if programDateTime.IsZero() {
+		return result
+	}
+
+	segmentStart := programDateTime.Add(dateRangeTolerance)
+	segmentEnd := programDateTime.Add(time.Duration(duration*float64(time.Second)) - dateRangeTolerance)
+	for _, interval := range dateRanges {
+		instant := interval.end.Equal(interval.start)
+		if interval.start.Before(segmentEnd) && (interval.end.After(segmentStart) || (instant && !interval.start.Before(programDateTime.Add(-dateRangeTolerance)))) {
+			result = append(result, interval.node)
+		}
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package playlist_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	pl "github.com/globocom/go-m3u8/playlist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentInfos(t *testing.T) {
+	manifest := `#EXTM3U
+#EXT-X-VERSION:7
+#EXT-X-TARGETDURATION:4
+#EXT-X-DISCONTINUITY-SEQUENCE:10
+#EXT-X-PROGRAM-DATE-TIME:2025-07-01T10:00:00Z
+#EXT-X-DATERANGE:ID="program",START-DATE="2025-07-01T10:00:00Z",END-DATE="2025-07-01T10:00:08Z"
+#EXT-X-MAP:URI="init-1.mp4"
+#EXT-X-KEY:METHOD=SAMPLE-AES,URI="skd://key-1",KEYFORMAT="com.apple.streamingkeydelivery",KEYFORMATVERSIONS="1"
+#EXT-X-KEY:METHOD=SAMPLE-AES,URI="data:text/plain;base64,AAAA",KEYFORMAT="urn:uuid:edef8ba9-79d6-4ace-a3c8-27dcd51d21ed",KEYFORMATVERSIONS="1"
+#EXT-X-BYTERANGE:1000@0
+#EXTINF:4,
+segment.mp4
+#EXT-X-KEY:METHOD=SAMPLE-AES,URI="skd://key-2",KEYFORMAT="com.apple.streamingkeydelivery",KEYFORMATVERSIONS="1"
+#EXT-X-BYTERANGE:1000
+#EXTINF:4,
+segment.mp4
+#EXT-X-DISCONTINUITY
+#EXT-X-MAP:URI="init-2.mp4"
+#EXT-X-KEY:METHOD=NONE
+#EXT-X-DATERANGE:ID="splice",START-DATE="2025-07-01T10:00:09Z"
+#EXTINF:4,
+segment-3.mp4
+#EXT-X-PROGRAM-DATE-TIME:2025-07-01T11:00:00Z
+#EXTINF:4,
+segment-4.mp4`
+
+	playlist, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+
+	maps := playlist.FindAll("Map")
+	keys := playlist.EncryptionTags()
+	dateRanges := playlist.FindAll("DateRange")
+	segments := playlist.Segments()
+
+	infos := make([]string, 0)
+	for info := range playlist.SegmentInfos() {
+		infos = append(infos, info.Node.HLSElement.URI)
+	}
+	assert.Equal(t, []string{"segment.mp4", "segment.mp4", "segment-3.mp4", "segment-4.mp4"}, infos)
+
+	info, found := playlist.SegmentInfo(segments[0])
+	assert.True(t, found)
+	assert.Equal(t, maps[0], info.Map)
+	assert.Equal(t, keys[:2], info.Keys)
+	assert.False(t, info.Discontinuity)
+	assert.Equal(t, 10, info.DiscontinuitySequence)
+	assert.Equal(t, time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC), info.ProgramDateTime)
+	assert.Equal(t, &pl.ByteRangeData{Length: 1000, Offset: 0, HasOffset: true}, info.ByteRange)
+	assert.Equal(t, dateRanges[:1], info.DateRanges)
+
+	// a key replaces the one with the same KEYFORMAT, and the byte range continues the previous one
+	info, found = playlist.SegmentInfo(segments[1])
+	assert.True(t, found)
+	assert.Equal(t, maps[0], info.Map)
+	assert.Equal(t, keys[1:3], info.Keys)
+	assert.Equal(t, time.Date(2025, 7, 1, 10, 0, 4, 0, time.UTC), info.ProgramDateTime)
+	assert.Equal(t, &pl.ByteRangeData{Length: 1000, Offset: 1000, HasOffset: true}, info.ByteRange)
+	assert.Equal(t, dateRanges[:1], info.DateRanges)
+
+	// the discontinuity starts a new init section, and METHOD=NONE ends the encryption
+	info, found = playlist.SegmentInfo(segments[2])
+	assert.True(t, found)
+	assert.Equal(t, maps[1], info.Map)
+	assert.Empty(t, info.Keys)
+	assert.True(t, info.Discontinuity)
+	assert.Equal(t, 11, info.DiscontinuitySequence)
+	assert.Equal(t, time.Date(2025, 7, 1, 10, 0, 8, 0, time.UTC), info.ProgramDateTime)
+	assert.Nil(t, info.ByteRange)
+	assert.Equal(t, dateRanges[1:], info.DateRanges)
+
+	// the program date time is the one computed when parsing, extrapolated from the first tag
+	info, found = playlist.SegmentInfo(segments[3])
+	assert.True(t, found)
+	assert.False(t, info.Discontinuity)
+	assert.Equal(t, 11, info.DiscontinuitySequence)
+	assert.Equal(t, time.Date(2025, 7, 1, 10, 0, 12, 0, time.UTC), info.ProgramDateTime)
+	assert.Empty(t, info.DateRanges)
+
+	_, found = playlist.SegmentInfo(maps[0])
+	assert.False(t, found)
+
+	// segments before the first program date time have none
+	manifest = "#EXTM3U\n#EXT-X-TARGETDURATION:4\n#EXTINF:4,\nsegment-1.ts\n#EXT-X-PROGRAM-DATE-TIME:2025-07-01T10:00:04Z\n#EXTINF:4,\nsegment-2.ts"
+	playlist, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+
+	info, found = playlist.SegmentInfo(playlist.Segments()[0])
+	assert.True(t, found)
+	assert.True(t, info.ProgramDateTime.IsZero())
+}
+
+func TestSegmentInfos_AdBreak(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/withEncryptionAndSCTE35.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	adBreak := playlist.Breaks()[0]
+	for info := range playlist.SegmentInfos() {
+		_, insideBreak := playlist.FindNodeInsideAdBreak(info.Node)
+		assert.Equal(t, insideBreak, len(info.DateRanges) > 0, info.Node.HLSElement.URI)
+		if insideBreak {
+			assert.Equal(t, adBreak, info.DateRanges[0])
+		}
+		assert.Len(t, info.Keys, 1)
+	}
+}
@@ -0,0 +1,78 @@
+package playlist
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/globocom/go-m3u8/node"
+)
+
+// METHODS FOR SESSION DATA
+
+const (
+	SessionDataFormatJSON = "JSON"
+	SessionDataFormatRaw  = "RAW"
+)
+
+// SessionDataFetcher retrieves the resource referenced by the URI attribute of a SessionData (#EXT-X-SESSION-DATA) tag.
+// It is supplied by the caller, so the library does not perform any I/O by itself.
+type SessionDataFetcher func(uri string) ([]byte, error)
+
+// Returns a SessionDataFetcher that reads the resources from the given file system.
+// URIs are resolved as slash-separated paths relative to the root of fsys.
+func FSFetcher(fsys fs.FS) SessionDataFetcher {
+	return func(uri string) ([]byte, error) {
+		return fs.ReadFile(fsys, strings.TrimPrefix(path.Clean(uri), "/"))
+	}
+}
+
+// Returns all SessionData (#EXT-X-SESSION-DATA) nodes in the playlist
+func (p *Playlist) SessionData() []*node.Node {
+	return p.FindAll("SessionData")
+}
+
+// Returns the SessionData (#EXT-X-SESSION-DATA) node with the given DATA-ID and LANGUAGE if it exists, otherwise returns nil and false.
+// An empty language matches the SessionData tag without the LANGUAGE attribute.
+func (p *Playlist) SessionDataByID(dataID, language string) (*node.Node, bool) {
+	for _, node := range p.SessionData() {
+		if node.HLSElement.Attrs["DATA-ID"] == dataID && node.HLSElement.Attrs["LANGUAGE"] == language {
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+// Loads the JSON resource referenced by the URI attribute of the SessionData (#EXT-X-SESSION-DATA) node with the given
+// DATA-ID and LANGUAGE (see SessionDataByID) using the fetcher, and decodes it into v.
+//
+// The FORMAT attribute defaults to JSON when absent. SessionData nodes with a VALUE attribute or with RAW format
+// can't be decoded, since their data is not a JSON resource.
+func (p *Playlist) LoadSessionData(dataID, language string, fetch SessionDataFetcher, v any) error {
+	node, found := p.SessionDataByID(dataID, language)
+	if !found {
+		return fmt.Errorf("session data %s not found", dataID)
+	}
+	attrs := node.HLSElement.Attrs
+
+	uri, exists := attrs["URI"]
+	if !exists {
+		return fmt.Errorf("session data %s has no URI attribute", attrs["DATA-ID"])
+	}
+
+	if format := attrs["FORMAT"]; format != "" && format != SessionDataFormatJSON {
+		return fmt.Errorf("session data %s has %s format, not JSON", attrs["DATA-ID"], format)
+	}
+
+	data, err := fetch(uri)
+	if err != nil {
+		return fmt.Errorf("failed to fetch session data %s: %w", attrs["DATA-ID"], err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode session data %s: %w", attrs["DATA-ID"], err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package playlist_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	pl "github.com/globocom/go-m3u8/playlist"
+	"github.com/stretchr/testify/assert"
+)
+
+type chapter struct {
+	Title string  `json:"title"`
+	Start float64 `json:"start"`
+}
+
+func TestSessionData(t *testing.T) {
+	file, _ := os.Open("./../mocks/multivariant/withSessionData.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	assert.Len(t, playlist.SessionData(), 4)
+
+	node, found := playlist.SessionDataByID("com.globo.title", "en")
+	assert.True(t, found)
+	assert.Equal(t, "National News", node.HLSElement.Attrs["VALUE"])
+
+	node, found = playlist.SessionDataByID("com.globo.chapters", "")
+	assert.True(t, found)
+	assert.Equal(t, "sessiondata/chapters.json", node.HLSElement.Attrs["URI"])
+
+	node, found = playlist.SessionDataByID("com.globo.title", "es")
+	assert.False(t, found)
+	assert.Nil(t, node)
+}
+
+func TestLoadSessionData(t *testing.T) {
+	file, _ := os.Open("./../mocks/multivariant/withSessionData.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	fetcher := pl.FSFetcher(os.DirFS("./../mocks/multivariant"))
+
+	// session data with JSON resource
+	var chapters []chapter
+	err = playlist.LoadSessionData("com.globo.chapters", "", fetcher, &chapters)
+	assert.NoError(t, err)
+	assert.Equal(t, []chapter{{Title: "Opening", Start: 0}, {Title: "Headlines", Start: 95.5}}, chapters)
+
+	// session data with JSON resource and custom fetcher
+	requested := ""
+	custom := func(uri string) ([]byte, error) {
+		requested = uri
+		return []byte(`[{"title": "Sports", "start": 10}]`), nil
+	}
+	err = playlist.LoadSessionData("com.globo.chapters", "", custom, &chapters)
+	assert.NoError(t, err)
+	assert.Equal(t, "sessiondata/chapters.json", requested)
+	assert.Equal(t, []chapter{{Title: "Sports", Start: 10}}, chapters)
+
+	// session data with failing fetcher
+	failing := func(uri string) ([]byte, error) {
+		return nil, errors.New("not found")
+	}
+	err = playlist.LoadSessionData("com.globo.chapters", "", failing, &chapters)
+	assert.Error(t, err)
+
+	// session data with invalid JSON resource
+	invalid := func(uri string) ([]byte, error) {
+		return []byte(`{"title":`), nil
+	}
+	err = playlist.LoadSessionData("com.globo.chapters", "", invalid, &chapters)
+	assert.Error(t, err)
+
+	// session data with RAW resource
+	err = playlist.LoadSessionData("com.globo.thumbnail", "", fetcher, &chapters)
+	assert.Error(t, err)
+
+	// session data with VALUE
+	err = playlist.LoadSessionData("com.globo.title", "pt", fetcher, &chapters)
+	assert.Error(t, err)
+
+	// session data not in the playlist
+	err = playlist.LoadSessionData("com.globo.title", "es", fetcher, &chapters)
+	assert.ErrorContains(t, err, "session data com.globo.title not found")
+}
@@ -0,0 +1,241 @@
+package playlist
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/globocom/go-m3u8/node"
+)
+
+// METHODS FOR PLAYLIST VALIDATION
+
+// Rules checked by Validate.
+const (
+	RuleMissingIdentifier       = "missing-extm3u"
+	RuleMixedPlaylistTags       = "mixed-playlist-tags"
+	RuleDuplicateTag            = "duplicate-tag"
+	RuleMissingTargetDuration   = "missing-target-duration"
+	RuleSegmentExceedsTarget    = "segment-exceeds-target-duration"
+	RuleVersionTooLow           = "version-too-low"
+	RuleUndefinedRenditionGroup = "undefined-rendition-group"
+)
+
+// Violation describes a MUST or MUST NOT rule of RFC 8216bis that the playlist breaks.
+// Node is the offending element, or nil when the rule applies to the whole playlist.
+type Violation struct {
+	Rule    string
+	Message string
+	Node    *node.Node
+}
+
+// Tags that MUST NOT appear in a Multivariant Playlist.
+var mediaPlaylistElements = []string{
+	"TargetDuration",
+	"MediaSequence",
+	"DiscontinuitySequence",
+	"Endlist",
+	"PlaylistType",
+	"IFramesOnly",
+	"PartInf",
+	"ServerControl",
+	"ExtInf",
+	"Discontinuity",
+	"ProgramDateTime",
+	"Key",
+	"Map",
+	"Part",
+	"DateRange",
+	"PreloadHint",
+	"Skip",
+	"RenditionReport",
+}
+
+// Tags that MUST NOT appear in a Media Playlist.
+var multivariantPlaylistElements = []string{
+	"StreamInf",
+	"IFrameStreamInf",
+	"Media",
+	"SessionData",
+	"SessionKey",
+	"ContentSteering",
+}
+
+// Tags that MUST NOT appear more than once in a playlist.
+var uniqueElements = []string{
+	"Version",
+	"TargetDuration",
+	"MediaSequence",
+	"DiscontinuitySequence",
+	"Endlist",
+	"PlaylistType",
+	"IFramesOnly",
+	"PartInf",
+	"ServerControl",
+	"IndependentSegments",
+	"Start",
+	"Skip",
+	"ContentSteering",
+}
+
+// Rendition group attributes of the Variant Streams, named after the TYPE of the Renditions they refer to.
+var renditionGroupAttributes = []string{"AUDIO", "VIDEO", "SUBTITLES", "CLOSED-CAPTIONS"}
+
+// Checks the playlist against the MUST and MUST NOT rules of RFC 8216bis, and returns the violations found,
+// in playlist order for each rule. An empty result means the playlist is valid for the checked rules:
+//   - The first line MUST be the #EXTM3U tag. Blank lines before it are only detected in playlists parsed
+//     in lossless mode, as the default parser does not keep them.
+//   - Media Playlist and Multivariant Playlist tags MUST NOT appear in the same playlist.
+//   - Playlist tags (e.g. #EXT-X-TARGETDURATION) MUST NOT appear more than once.
+//   - Media Playlists MUST have the #EXT-X-TARGETDURATION tag, and each #EXTINF duration, rounded to the nearest
+//     integer, MUST be less than or equal to it.
+//   - #EXT-X-VERSION MUST be high enough for the features used by the playlist (see Section 8).
+//   - The AUDIO, VIDEO, SUBTITLES and CLOSED-CAPTIONS attributes of the Variant Streams MUST match the GROUP-ID of
+//     a Rendition (#EXT-X-MEDIA) of the same TYPE.
+func Validate(p *Playlist) []Violation {
+	violations := make([]Violation, 0)
+	if p == nil || p.Head == nil {
+		return append(violations, Violation{Rule: RuleMissingIdentifier, Message: "playlist is empty"})
+	}
+
+	violations = append(violations, validateIdentifier(p)...)
+	violations = append(violations, validatePlaylistKind(p)...)
+	violations = append(violations, validateUniqueTags(p)...)
+	violations = append(violations, validateTargetDuration(p)...)
+	violations = append(violations, validateVersion(p)...)
+	violations = append(violations, validateRenditionGroups(p)...)
+	return violations
+}
+
+func validateIdentifier(p *Playlist) []Violation {
+	head := p.Head
+	if head.HLSElement.Name == "M3u8Identifier" && (head.HLSElement.Raw == nil || head.HLSElement.Raw.Leading == 0) {
+		return nil
+	}
+	return []Violation{{Rule: RuleMissingIdentifier, Message: "the first line of the playlist must be #EXTM3U", Node: head}}
+}
+
+func validatePlaylistKind(p *Playlist) []Violation {
+	var media, multivariant *node.Node
+	for current := p.Head; current != nil; current = current.Next {
+		if media == nil && slices.Contains(mediaPlaylistElements, current.HLSElement.Name) {
+			media = current
+		}
+		if multivariant == nil && slices.Contains(multivariantPlaylistElements, current.HLSElement.Name) {
+			multivariant = current
+		}
+	}
+	if media == nil || multivariant == nil {
+		return nil
+	}
+
+	// the tag that shows up last is the one out of place
+	offending := multivariant
+	for current := multivariant; current != nil; current = current.Next {
+		if current == media {
+			offending = media
+			break
+		}
+	}
+	return []Violation{{
+		Rule:    RuleMixedPlaylistTags,
+		Message: fmt.Sprintf("playlist has both Media Playlist (%s) and Multivariant Playlist (%s) tags", media.HLSElement.Name, multivariant.HLSElement.Name),
+		Node:    offending,
+	}}
+}
+
+func validateUniqueTags(p *Playlist) []Violation {
+	violations := make([]Violation, 0)
+	seen := make(map[string]bool)
+	for current := p.Head; current != nil; current = current.Next {
+		name := current.HLSElement.Name
+		if !slices.Contains(uniqueElements, name) {
+			continue
+		}
+		if seen[name] {
+			violations = append(violations, Violation{
+				Rule:    RuleDuplicateTag,
+				Message: fmt.Sprintf("%s tag must not appear more than once", name),
+				Node:    current,
+			})
+		}
+		seen[name] = true
+	}
+	return violations
+}
+
+func validateTargetDuration(p *Playlist) []Violation {
+	segments := p.Segments()
+	if len(segments) == 0 {
+		return nil
+	}
+
+	node, found := p.Find("TargetDuration")
+	if !found {
+		return []Violation{{Rule: RuleMissingTargetDuration, Message: "media playlist must have the #EXT-X-TARGETDURATION tag"}}
+	}
+	targetDuration, err := strconv.Atoi(node.HLSElement.Attrs["#EXT-X-TARGETDURATION"])
+	if err != nil {
+		return []Violation{{
+			Rule:    RuleMissingTargetDuration,
+			Message: fmt.Sprintf("invalid target duration: %s", node.HLSElement.Attrs["#EXT-X-TARGETDURATION"]),
+			Node:    node,
+		}}
+	}
+
+	violations := make([]Violation, 0)
+	for _, segment := range segments {
+		if int(math.Round(segmentDuration(segment))) > targetDuration {
+			violations = append(violations, Violation{
+				Rule:    RuleSegmentExceedsTarget,
+				Message: fmt.Sprintf("segment %s duration %s exceeds the target duration %d", segment.HLSElement.URI, segment.HLSElement.Attrs["Duration"], targetDuration),
+				Node:    segment,
+			})
+		}
+	}
+	return violations
+}
+
+func validateVersion(p *Playlist) []Violation {
+	version := p.Version()
+	violations := make([]Violation, 0)
+	for _, requirement := range versionRequirements(p) {
+		if version < requirement.version {
+			violations = append(violations, Violation{
+				Rule:    RuleVersionTooLow,
+				Message: fmt.Sprintf("use of %s requires a compatibility version of %d or greater, but playlist version is %d", requirement.feature, requirement.version, version),
+				Node:    requirement.node,
+			})
+		}
+	}
+	return violations
+}
+
+func validateRenditionGroups(p *Playlist) []Violation {
+	groups := make(map[string]bool)
+	for _, media := range p.MediaGroups() {
+		groups[media.HLSElement.Attrs["TYPE"]+"/"+media.HLSElement.Attrs["GROUP-ID"]] = true
+	}
+
+	violations := make([]Violation, 0)
+	for current := p.Head; current != nil; current = current.Next {
+		if current.HLSElement.Name != "StreamInf" && current.HLSElement.Name != "IFrameStreamInf" {
+			continue
+		}
+		for _, attribute := range renditionGroupAttributes {
+			groupID := current.HLSElement.Attrs[attribute]
+			if groupID == "" || (attribute == "CLOSED-CAPTIONS" && groupID == "NONE") {
+				continue
+			}
+			if !groups[attribute+"/"+groupID] {
+				violations = append(violations, Violation{
+					Rule:    RuleUndefinedRenditionGroup,
+					Message: fmt.Sprintf("%s group %s has no #EXT-X-MEDIA tag with TYPE=%s", attribute, groupID, attribute),
+					Node:    current,
+				})
+			}
+		}
+	}
+	return violations
+}
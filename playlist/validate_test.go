@@ -0,0 +1,163 @@
+package playlist_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	pl "github.com/globocom/go-m3u8/playlist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	for _, mock := range []string{
+		"./../mocks/media/media.m3u8",
+		"./../mocks/media/withByteRange.m3u8",
+		"./../mocks/multivariant/multivariant.m3u8",
+		"./../mocks/multivariant/withAllStreamInfAttributes.m3u8",
+	} {
+		file, _ := os.Open(mock)
+		playlist, err := m3u8.ParsePlaylist(file)
+		assert.NoError(t, err)
+		assert.Empty(t, pl.Validate(playlist), mock)
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+		rules    []string
+		nodes    []string
+	}{
+		{
+			name: "missing identifier",
+			manifest: `#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXTINF:6.0,
+segment1.ts`,
+			rules: []string{pl.RuleMissingIdentifier},
+			nodes: []string{"Version"},
+		},
+		{
+			name: "mixed playlist tags",
+			manifest: `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXTINF:6.0,
+segment1.ts
+#EXT-X-STREAM-INF:BANDWIDTH=1280000
+low.m3u8`,
+			rules: []string{pl.RuleMixedPlaylistTags},
+			nodes: []string{"StreamInf"},
+		},
+		{
+			name: "duplicate tags",
+			manifest: `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXT-X-MEDIA-SEQUENCE:10
+#EXT-X-TARGETDURATION:6
+#EXTINF:6.0,
+segment1.ts
+#EXT-X-ENDLIST
+#EXT-X-ENDLIST`,
+			rules: []string{pl.RuleDuplicateTag, pl.RuleDuplicateTag},
+			nodes: []string{"TargetDuration", "Endlist"},
+		},
+		{
+			name: "segments exceeding the target duration",
+			manifest: `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXTINF:6.4,
+segment1.ts
+#EXTINF:6.5,
+segment2.ts`,
+			rules: []string{pl.RuleSegmentExceedsTarget},
+			nodes: []string{"ExtInf"},
+		},
+		{
+			name: "missing target duration",
+			manifest: `#EXTM3U
+#EXT-X-VERSION:3
+#EXTINF:6.0,
+segment1.ts`,
+			rules: []string{pl.RuleMissingTargetDuration},
+			nodes: []string{""},
+		},
+		{
+			name: "version too low",
+			manifest: `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXT-X-MAP:URI="init.mp4"
+#EXT-X-KEY:METHOD=SAMPLE-AES,URI="skd://key",KEYFORMAT="com.apple.streamingkeydelivery",KEYFORMATVERSIONS="1"
+#EXTINF:6.0,
+#EXT-X-BYTERANGE:1000@0
+segment1.mp4`,
+			rules: []string{pl.RuleVersionTooLow, pl.RuleVersionTooLow, pl.RuleVersionTooLow},
+			nodes: []string{"Map", "Key", "ExtInf"},
+		},
+		{
+			name: "missing version",
+			manifest: `#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXTINF:5.5,
+segment1.ts`,
+			rules: []string{pl.RuleVersionTooLow},
+			nodes: []string{"ExtInf"},
+		},
+		{
+			name: "undefined rendition groups",
+			manifest: `#EXTM3U
+#EXT-X-VERSION:4
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aac",NAME="English",URI="audio/en.m3u8"
+#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="aac",NAME="English",URI="subs/en.m3u8"
+#EXT-X-STREAM-INF:BANDWIDTH=1280000,AUDIO="aac",CLOSED-CAPTIONS=NONE
+low.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=2560000,AUDIO="ac3",SUBTITLES="subs"
+high.m3u8`,
+			rules: []string{pl.RuleUndefinedRenditionGroup, pl.RuleUndefinedRenditionGroup},
+			nodes: []string{"StreamInf", "StreamInf"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlist, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(tt.manifest)))
+			assert.NoError(t, err)
+
+			violations := pl.Validate(playlist)
+			rules := make([]string, 0, len(violations))
+			nodes := make([]string, 0, len(violations))
+			for _, violation := range violations {
+				rules = append(rules, violation.Rule)
+				if violation.Node == nil {
+					nodes = append(nodes, "")
+				} else {
+					nodes = append(nodes, violation.Node.HLSElement.Name)
+				}
+			}
+			assert.Equal(t, tt.rules, rules)
+			assert.Equal(t, tt.nodes, nodes)
+		})
+	}
+
+	// an empty playlist has no identifier
+	violations := pl.Validate(pl.NewPlaylist())
+	assert.Len(t, violations, 1)
+	assert.Equal(t, pl.RuleMissingIdentifier, violations[0].Rule)
+
+	// blank lines before the identifier are only kept in lossless mode
+	manifest := "\n#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:6.0,\nsegment1.ts"
+	playlist, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+	assert.Empty(t, pl.Validate(playlist))
+
+	playlist, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)), m3u8.WithLossless())
+	assert.NoError(t, err)
+	violations = pl.Validate(playlist)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, pl.RuleMissingIdentifier, violations[0].Rule)
+}
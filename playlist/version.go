@@ -0,0 +1,128 @@
+package playlist
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/globocom/go-m3u8/node"
+)
+
+// METHODS FOR COMPATIBILITY VERSION
+
+// versionRequirement is a feature that requires a minimum compatibility version.
+type versionRequirement struct {
+	version int
+	feature string
+	node    *node.Node
+}
+
+// Returns the features used by the playlist that require a compatibility version greater than 1,
+// as listed in Section 8 of RFC 8216bis.
+func versionRequirements(p *Playlist) []versionRequirement {
+	_, iFramesOnly := p.Find("IFramesOnly")
+
+	requirements := make([]versionRequirement, 0)
+	for current := p.Head; current != nil; current = current.Next {
+		attrs := current.HLSElement.Attrs
+		switch current.HLSElement.Name {
+		case "Key":
+			if attrs["IV"] != "" {
+				requirements = append(requirements, versionRequirement{2, "the IV attribute of #EXT-X-KEY", current})
+			}
+			if attrs["KEYFORMAT"] != "" || attrs["KEYFORMATVERSIONS"] != "" {
+				requirements = append(requirements, versionRequirement{5, "the KEYFORMAT and KEYFORMATVERSIONS attributes of #EXT-X-KEY", current})
+			}
+		case "ExtInf":
+			if strings.Contains(attrs["Duration"], ".") {
+				requirements = append(requirements, versionRequirement{3, "floating-point #EXTINF durations", current})
+			}
+			if attrs["ByteRange"] != "" {
+				requirements = append(requirements, versionRequirement{4, "the #EXT-X-BYTERANGE tag", current})
+			}
+		case "IFramesOnly":
+			requirements = append(requirements, versionRequirement{4, "the #EXT-X-I-FRAMES-ONLY tag", current})
+		case "Map":
+			if iFramesOnly {
+				requirements = append(requirements, versionRequirement{5, "the #EXT-X-MAP tag", current})
+			} else {
+				requirements = append(requirements, versionRequirement{6, "the #EXT-X-MAP tag without #EXT-X-I-FRAMES-ONLY", current})
+			}
+		case "Media":
+			if strings.HasPrefix(attrs["INSTREAM-ID"], "SERVICE") {
+				requirements = append(requirements, versionRequirement{7, "SERVICE values for the INSTREAM-ID attribute of #EXT-X-MEDIA", current})
+			}
+		case "VariableDefine":
+			requirements = append(requirements, versionRequirement{8, "the #EXT-X-DEFINE tag", current})
+			if attrs["QUERYPARAM"] != "" {
+				requirements = append(requirements, versionRequirement{11, "the QUERYPARAM attribute of #EXT-X-DEFINE", current})
+			}
+		case "Skip":
+			requirements = append(requirements, versionRequirement{9, "the #EXT-X-SKIP tag", current})
+			if _, exists := attrs["RECENTLY-REMOVED-DATERANGES"]; exists {
+				requirements = append(requirements, versionRequirement{10, "the RECENTLY-REMOVED-DATERANGES attribute of #EXT-X-SKIP", current})
+			}
+		}
+	}
+	return requirements
+}
+
+// Returns the compatibility version of the playlist, from its Version (#EXT-X-VERSION) tag.
+// Playlists without the tag, or with an invalid value, are version 1.
+func (p *Playlist) Version() int {
+	version, err := strconv.Atoi(p.VersionValue())
+	if err != nil {
+		return 1
+	}
+	return version
+}
+
+// Returns the minimum compatibility version required by the features present in the playlist,
+// as listed in Section 8 of RFC 8216bis (e.g. 3 for floating-point #EXTINF durations, 6 for #EXT-X-MAP in
+// a playlist without #EXT-X-I-FRAMES-ONLY).
+func (p *Playlist) RequiredVersion() int {
+	required := 1
+	for _, requirement := range versionRequirements(p) {
+		required = max(required, requirement.version)
+	}
+	return required
+}
+
+// Raises the Version (#EXT-X-VERSION) tag to the RequiredVersion of the playlist, and returns the resulting version.
+// When the playlist has no Version tag and requires a version greater than 1, one is inserted after the #EXTM3U tag.
+// The version is never lowered.
+func (p *Playlist) UpdateVersion() int {
+	version, required := p.Version(), p.RequiredVersion()
+	if required <= version {
+		return version
+	}
+
+	if node, found := p.VersionTag(); found {
+		node.HLSElement.Attrs["#EXT-X-VERSION"] = strconv.Itoa(required)
+		return required
+	}
+
+	p.insertAfterIdentifier(newVersionNode(required))
+	return required
+}
+
+// Returns a new Version (#EXT-X-VERSION) node with the given version.
+func newVersionNode(version int) *node.Node {
+	return &node.Node{
+		HLSElement: &node.HLSElement{
+			Name:  "Version",
+			Attrs: map[string]string{"#EXT-X-VERSION": strconv.Itoa(version)},
+		},
+	}
+}
+
+// Inserts the node right after the #EXTM3U tag, or at the beginning of the playlist if it does not have one.
+func (p *Playlist) insertAfterIdentifier(node *node.Node) {
+	switch {
+	case p.Head == nil:
+		p.Insert(node)
+	case p.Head.HLSElement.Name == "M3u8Identifier":
+		p.InsertAfter(p.Head, node)
+	default:
+		p.InsertBefore(p.Head, node)
+	}
+}
@@ -0,0 +1,115 @@
+package playlist_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiredVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		version  int
+		required int
+	}{
+		{
+			name:     "integer durations",
+			manifest: "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\nsegment1.ts",
+			version:  1,
+			required: 1,
+		},
+		{
+			name:     "floating-point durations",
+			manifest: "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXTINF:5.5,\nsegment1.ts",
+			version:  3,
+			required: 3,
+		},
+		{
+			name:     "key with IV",
+			manifest: "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXT-X-KEY:METHOD=AES-128,URI=\"key.bin\",IV=0x0123456789abcdef0123456789abcdef\n#EXTINF:6,\nsegment1.ts",
+			version:  1,
+			required: 2,
+		},
+		{
+			name:     "byte range",
+			manifest: "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\n#EXT-X-BYTERANGE:1000@0\nsegment.ts",
+			version:  3,
+			required: 4,
+		},
+		{
+			name:     "map with i-frames only",
+			manifest: "#EXTM3U\n#EXT-X-VERSION:4\n#EXT-X-TARGETDURATION:6\n#EXT-X-I-FRAMES-ONLY\n#EXT-X-MAP:URI=\"init.mp4\"\n#EXTINF:6,\nsegment1.mp4",
+			version:  4,
+			required: 5,
+		},
+		{
+			name:     "map",
+			manifest: "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXT-X-MAP:URI=\"init.mp4\"\n#EXTINF:6,\nsegment1.mp4",
+			version:  3,
+			required: 6,
+		},
+		{
+			name:     "skip",
+			manifest: "#EXTM3U\n#EXT-X-VERSION:9\n#EXT-X-TARGETDURATION:6\n#EXT-X-SKIP:SKIPPED-SEGMENTS=2\n#EXTINF:6,\nsegment3.ts",
+			version:  9,
+			required: 9,
+		},
+		{
+			name:     "skip with recently removed date ranges",
+			manifest: "#EXTM3U\n#EXT-X-VERSION:9\n#EXT-X-TARGETDURATION:6\n#EXT-X-SKIP:SKIPPED-SEGMENTS=2,RECENTLY-REMOVED-DATERANGES=\"splice-1\"\n#EXTINF:6,\nsegment3.ts",
+			version:  9,
+			required: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlist, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(tt.manifest)))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.version, playlist.Version())
+			assert.Equal(t, tt.required, playlist.RequiredVersion())
+		})
+	}
+
+	file, _ := os.Open("./../mocks/multivariant/withAllStreamInfAttributes.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, playlist.RequiredVersion(), playlist.Version())
+}
+
+func TestUpdateVersion(t *testing.T) {
+	// the version tag is raised
+	manifest := "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXT-X-MAP:URI=\"init.mp4\"\n#EXTINF:6,\nsegment1.mp4"
+	playlist, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, playlist.UpdateVersion())
+	assert.Equal(t, "6", playlist.VersionValue())
+
+	// the version tag is inserted after #EXTM3U
+	manifest = "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:5.5,\nsegment1.ts"
+	playlist, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, playlist.UpdateVersion())
+	assert.Equal(t, "Version", playlist.Head.Next.HLSElement.Name)
+	assert.Equal(t, "3", playlist.VersionValue())
+
+	// the version is never lowered
+	manifest = "#EXTM3U\n#EXT-X-VERSION:7\n#EXT-X-TARGETDURATION:6\n#EXTINF:5.5,\nsegment1.ts"
+	playlist, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+	assert.Equal(t, 7, playlist.UpdateVersion())
+	assert.Equal(t, "7", playlist.VersionValue())
+
+	// playlists that only use version 1 features have no version tag inserted
+	manifest = "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\nsegment1.ts"
+	playlist, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, playlist.UpdateVersion())
+	_, found := playlist.VersionTag()
+	assert.False(t, found)
+}
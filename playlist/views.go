@@ -0,0 +1,415 @@
+package playlist
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/globocom/go-m3u8/node"
+)
+
+// METHODS FOR TYPED PLAYLIST VIEWS
+
+// MediaPlaylist is a typed view of a Media Playlist. Its values are read from the playlist nodes when the view is
+// created, and its setters change both the view and the nodes, so the changes are picked up by EncodePlaylist.
+// Changes made to the nodes afterwards are not reflected in the view.
+type MediaPlaylist struct {
+	playlist              *Playlist
+	TargetDuration        int
+	MediaSequence         int
+	DiscontinuitySequence int
+	PlaylistType          string
+	Segments              []Segment
+}
+
+// Segment is a typed view of a Media Segment (#EXTINF) node.
+type Segment struct {
+	playlist        *Playlist
+	Node            *node.Node
+	URI             string
+	Duration        float64
+	Title           string
+	MediaSequence   int
+	ProgramDateTime time.Time
+	ByteRange       string
+	Gap             bool
+}
+
+// MultivariantPlaylist is a typed view of a Multivariant Playlist, with the same semantics as MediaPlaylist.
+type MultivariantPlaylist struct {
+	playlist       *Playlist
+	Variants       []Variant
+	Renditions     []Rendition
+	IFrameVariants []IFrameVariant
+}
+
+// Variant is a typed view of a Variant Stream (#EXT-X-STREAM-INF) node.
+type Variant struct {
+	Node             *node.Node
+	URI              string
+	Bandwidth        int
+	AverageBandwidth int
+	Codecs           []string
+	Resolution       string
+	FrameRate        float64
+	Audio            string
+	Video            string
+	Subtitles        string
+	ClosedCaptions   string
+	PathwayID        string
+}
+
+// Rendition is a typed view of a Rendition (#EXT-X-MEDIA) node.
+type Rendition struct {
+	Node       *node.Node
+	Type       string
+	GroupID    string
+	Name       string
+	Language   string
+	URI        string
+	Default    bool
+	Autoselect bool
+}
+
+// IFrameVariant is a typed view of an I-frame Variant Stream (#EXT-X-I-FRAME-STREAM-INF) node.
+type IFrameVariant struct {
+	Node             *node.Node
+	URI              string
+	Bandwidth        int
+	AverageBandwidth int
+	Codecs           []string
+	Resolution       string
+	Video            string
+}
+
+// Returns a typed view of the Media Playlist, or an error if the playlist is a Multivariant Playlist
+// or has invalid numeric attributes.
+func (p *Playlist) MediaPlaylist() (*MediaPlaylist, error) {
+	for _, name := range multivariantPlaylistElements {
+		if _, found := p.Find(name); found {
+			return nil, fmt.Errorf("not a media playlist: found %s tag", name)
+		}
+	}
+
+	media := &MediaPlaylist{playlist: p, PlaylistType: p.PlaylistTypeValue()}
+
+	var err error
+	if node, found := p.Find("TargetDuration"); found {
+		if media.TargetDuration, err = strconv.Atoi(node.HLSElement.Attrs["#EXT-X-TARGETDURATION"]); err != nil {
+			return nil, fmt.Errorf("invalid target duration: %w", err)
+		}
+	}
+	if value := p.MediaSequenceValue(); value != "" {
+		if media.MediaSequence, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("invalid media sequence: %w", err)
+		}
+	}
+	if value := p.DiscontinuitySequenceValue(); value != "" {
+		if media.DiscontinuitySequence, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("invalid discontinuity sequence: %w", err)
+		}
+	}
+
+	for _, node := range p.Segments() {
+		segment, err := newSegment(p, node)
+		if err != nil {
+			return nil, err
+		}
+		media.Segments = append(media.Segments, segment)
+	}
+	return media, nil
+}
+
+// Returns a typed view of the Multivariant Playlist, or an error if the playlist is a Media Playlist
+// or has invalid numeric attributes.
+func (p *Playlist) MultivariantPlaylist() (*MultivariantPlaylist, error) {
+	if _, found := p.Find("ExtInf"); found {
+		return nil, fmt.Errorf("not a multivariant playlist: found ExtInf tag")
+	}
+
+	multivariant := &MultivariantPlaylist{playlist: p}
+	for _, node := range p.Variants() {
+		variant, err := newVariant(node)
+		if err != nil {
+			return nil, err
+		}
+		multivariant.Variants = append(multivariant.Variants, variant)
+	}
+	for _, node := range p.MediaGroups() {
+		multivariant.Renditions = append(multivariant.Renditions, newRendition(node))
+	}
+	for _, node := range p.Keyframes() {
+		variant, err := newIFrameVariant(node)
+		if err != nil {
+			return nil, err
+		}
+		multivariant.IFrameVariants = append(multivariant.IFrameVariants, variant)
+	}
+	return multivariant, nil
+}
+
+// Sets the Target Duration (#EXT-X-TARGETDURATION) of the playlist, inserting the tag if it is missing.
+func (m *MediaPlaylist) SetTargetDuration(targetDuration int) {
+	m.TargetDuration = targetDuration
+	m.playlist.setHeaderTag("TargetDuration", "#EXT-X-TARGETDURATION", strconv.Itoa(targetDuration))
+}
+
+// Sets the Media Sequence Number (#EXT-X-MEDIA-SEQUENCE) of the playlist, inserting the tag if it is missing.
+// The Media Sequence Numbers of the segments are shifted accordingly.
+func (m *MediaPlaylist) SetMediaSequence(mediaSequence int) {
+	offset := mediaSequence - m.MediaSequence
+	m.MediaSequence = mediaSequence
+	m.playlist.setHeaderTag("MediaSequence", "#EXT-X-MEDIA-SEQUENCE", strconv.Itoa(mediaSequence))
+
+	for i := range m.Segments {
+		segment := &m.Segments[i]
+		segment.MediaSequence += offset
+		segment.Node.HLSElement.Details["MediaSequence"] = strconv.Itoa(segment.MediaSequence)
+	}
+	m.playlist.syncState()
+}
+
+// Sets the Discontinuity Sequence Number (#EXT-X-DISCONTINUITY-SEQUENCE) of the playlist, inserting the tag if it is missing.
+func (m *MediaPlaylist) SetDiscontinuitySequence(discontinuitySequence int) {
+	m.DiscontinuitySequence = discontinuitySequence
+	m.playlist.setHeaderTag("DiscontinuitySequence", "#EXT-X-DISCONTINUITY-SEQUENCE", strconv.Itoa(discontinuitySequence))
+	m.playlist.syncState()
+}
+
+// Sets the Playlist Type (#EXT-X-PLAYLIST-TYPE) of the playlist (i.e. EVENT or VOD), inserting the tag if it is missing.
+func (m *MediaPlaylist) SetPlaylistType(playlistType string) {
+	m.PlaylistType = playlistType
+	m.playlist.setHeaderTag("PlaylistType", "#EXT-X-PLAYLIST-TYPE", playlistType)
+}
+
+// Sets the URI of the segment.
+func (s *Segment) SetURI(uri string) {
+	s.URI = uri
+	s.Node.HLSElement.URI = uri
+}
+
+// Sets the duration (in seconds) of the segment, and recomputes the playlist counters (e.g. DVR).
+// The Details (e.g. ProgramDateTime) of the following segments are not recomputed.
+func (s *Segment) SetDuration(duration float64) {
+	s.Duration = duration
+	s.Node.HLSElement.Attrs["Duration"] = strconv.FormatFloat(duration, 'f', -1, 64)
+	s.playlist.syncState()
+}
+
+// Sets the title of the segment.
+func (s *Segment) SetTitle(title string) {
+	s.Title = title
+	s.Node.HLSElement.Attrs["Title"] = title
+}
+
+// Sets the URI of the Variant Stream.
+func (v *Variant) SetURI(uri string) {
+	v.URI = uri
+	v.Node.HLSElement.URI = uri
+}
+
+// Sets the BANDWIDTH attribute of the Variant Stream.
+func (v *Variant) SetBandwidth(bandwidth int) {
+	v.Bandwidth = bandwidth
+	v.Node.HLSElement.Attrs["BANDWIDTH"] = strconv.Itoa(bandwidth)
+}
+
+// Sets the AVERAGE-BANDWIDTH attribute of the Variant Stream. Zero removes it.
+func (v *Variant) SetAverageBandwidth(averageBandwidth int) {
+	v.AverageBandwidth = averageBandwidth
+	setOptionalAttr(v.Node, "AVERAGE-BANDWIDTH", averageBandwidth != 0, strconv.Itoa(averageBandwidth))
+}
+
+// Sets the CODECS attribute of the Variant Stream. An empty list removes it.
+func (v *Variant) SetCodecs(codecs []string) {
+	v.Codecs = codecs
+	setOptionalAttr(v.Node, "CODECS", len(codecs) > 0, strings.Join(codecs, ","))
+}
+
+// Sets the RESOLUTION attribute of the Variant Stream (e.g. 1920x1080). An empty value removes it.
+func (v *Variant) SetResolution(resolution string) {
+	v.Resolution = resolution
+	setOptionalAttr(v.Node, "RESOLUTION", resolution != "", resolution)
+}
+
+// Sets the URI attribute of the Rendition. An empty value removes it.
+func (r *Rendition) SetURI(uri string) {
+	r.URI = uri
+	setOptionalAttr(r.Node, "URI", uri != "", uri)
+}
+
+// Sets the NAME attribute of the Rendition.
+func (r *Rendition) SetName(name string) {
+	r.Name = name
+	r.Node.HLSElement.Attrs["NAME"] = name
+}
+
+// Sets the LANGUAGE attribute of the Rendition. An empty value removes it.
+func (r *Rendition) SetLanguage(language string) {
+	r.Language = language
+	setOptionalAttr(r.Node, "LANGUAGE", language != "", language)
+}
+
+// Sets the URI attribute of the I-frame Variant Stream.
+func (v *IFrameVariant) SetURI(uri string) {
+	v.URI = uri
+	v.Node.HLSElement.Attrs["URI"] = uri
+}
+
+// Sets the BANDWIDTH attribute of the I-frame Variant Stream.
+func (v *IFrameVariant) SetBandwidth(bandwidth int) {
+	v.Bandwidth = bandwidth
+	v.Node.HLSElement.Attrs["BANDWIDTH"] = strconv.Itoa(bandwidth)
+}
+
+func newSegment(p *Playlist, node *node.Node) (Segment, error) {
+	attrs, details := node.HLSElement.Attrs, node.HLSElement.Details
+
+	duration, err := strconv.ParseFloat(attrs["Duration"], 64)
+	if err != nil {
+		return Segment{}, fmt.Errorf("invalid duration for segment %s: %w", node.HLSElement.URI, err)
+	}
+	mediaSequence, err := strconv.Atoi(details["MediaSequence"])
+	if err != nil {
+		return Segment{}, fmt.Errorf("invalid media sequence for segment %s: %w", node.HLSElement.URI, err)
+	}
+	programDateTime, _ := time.Parse(time.RFC3339Nano, details["ProgramDateTime"])
+
+	return Segment{
+		playlist:        p,
+		Node:            node,
+		URI:             node.HLSElement.URI,
+		Duration:        duration,
+		Title:           attrs["Title"],
+		MediaSequence:   mediaSequence,
+		ProgramDateTime: programDateTime,
+		ByteRange:       attrs["ByteRange"],
+		Gap:             attrs["Gap"] == "YES",
+	}, nil
+}
+
+func newVariant(node *node.Node) (Variant, error) {
+	attrs := node.HLSElement.Attrs
+
+	bandwidth, averageBandwidth, err := bandwidthAttrs(node)
+	if err != nil {
+		return Variant{}, err
+	}
+	frameRate, err := optionalFloatAttr(attrs, "FRAME-RATE")
+	if err != nil {
+		return Variant{}, fmt.Errorf("invalid FRAME-RATE for variant %s: %w", node.HLSElement.URI, err)
+	}
+
+	return Variant{
+		Node:             node,
+		URI:              node.HLSElement.URI,
+		Bandwidth:        bandwidth,
+		AverageBandwidth: averageBandwidth,
+		Codecs:           codecsAttr(attrs),
+		Resolution:       attrs["RESOLUTION"],
+		FrameRate:        frameRate,
+		Audio:            attrs["AUDIO"],
+		Video:            attrs["VIDEO"],
+		Subtitles:        attrs["SUBTITLES"],
+		ClosedCaptions:   attrs["CLOSED-CAPTIONS"],
+		PathwayID:        attrs["PATHWAY-ID"],
+	}, nil
+}
+
+func newRendition(node *node.Node) Rendition {
+	attrs := node.HLSElement.Attrs
+	return Rendition{
+		Node:       node,
+		Type:       attrs["TYPE"],
+		GroupID:    attrs["GROUP-ID"],
+		Name:       attrs["NAME"],
+		Language:   attrs["LANGUAGE"],
+		URI:        attrs["URI"],
+		Default:    attrs["DEFAULT"] == "YES",
+		Autoselect: attrs["AUTOSELECT"] == "YES",
+	}
+}
+
+func newIFrameVariant(node *node.Node) (IFrameVariant, error) {
+	attrs := node.HLSElement.Attrs
+
+	bandwidth, averageBandwidth, err := bandwidthAttrs(node)
+	if err != nil {
+		return IFrameVariant{}, err
+	}
+
+	return IFrameVariant{
+		Node:             node,
+		URI:              attrs["URI"],
+		Bandwidth:        bandwidth,
+		AverageBandwidth: averageBandwidth,
+		Codecs:           codecsAttr(attrs),
+		Resolution:       attrs["RESOLUTION"],
+		Video:            attrs["VIDEO"],
+	}, nil
+}
+
+// Returns the BANDWIDTH and AVERAGE-BANDWIDTH attributes of a Variant Stream node. The latter is optional.
+func bandwidthAttrs(node *node.Node) (int, int, error) {
+	bandwidth, err := strconv.Atoi(node.HLSElement.Attrs["BANDWIDTH"])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid BANDWIDTH for %s: %w", node.HLSElement.Name, err)
+	}
+
+	averageBandwidth := 0
+	if value := node.HLSElement.Attrs["AVERAGE-BANDWIDTH"]; value != "" {
+		if averageBandwidth, err = strconv.Atoi(value); err != nil {
+			return 0, 0, fmt.Errorf("invalid AVERAGE-BANDWIDTH for %s: %w", node.HLSElement.Name, err)
+		}
+	}
+	return bandwidth, averageBandwidth, nil
+}
+
+func optionalFloatAttr(attrs map[string]string, key string) (float64, error) {
+	if attrs[key] == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(attrs[key], 64)
+}
+
+func codecsAttr(attrs map[string]string) []string {
+	if attrs["CODECS"] == "" {
+		return nil
+	}
+	return strings.Split(attrs["CODECS"], ",")
+}
+
+// Sets the attribute of the node to value when present is true, otherwise removes it.
+func setOptionalAttr(node *node.Node, key string, present bool, value string) {
+	if present {
+		node.HLSElement.Attrs[key] = value
+	} else {
+		delete(node.HLSElement.Attrs, key)
+	}
+}
+
+// Tags found at the top of a playlist, before its segments or variants.
+var headerElements = append([]string{"M3u8Identifier"}, playlistLevelElements...)
+
+// Sets the value of a playlist-level tag, inserting it at the end of the playlist-level tags at the top of the playlist
+// if it is missing.
+func (p *Playlist) setHeaderTag(name, tag, value string) {
+	if node, found := p.Find(name); found {
+		node.HLSElement.Attrs[tag] = value
+		return
+	}
+
+	var last *node.Node
+	for current := p.Head; current != nil && slices.Contains(headerElements, current.HLSElement.Name); current = current.Next {
+		last = current
+	}
+
+	newNode := p.NewNode(name, "", map[string]string{tag: value}, nil)
+	if last == nil {
+		p.insertAfterIdentifier(newNode)
+		return
+	}
+	p.InsertAfter(last, newNode)
+}
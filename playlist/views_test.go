@@ -0,0 +1,127 @@
+package playlist_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	pl "github.com/globocom/go-m3u8/playlist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaPlaylist(t *testing.T) {
+	file, _ := os.Open("./../mocks/media/media.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	media, err := playlist.MediaPlaylist()
+	assert.NoError(t, err)
+	dvr := playlist.DVR
+	assert.Equal(t, 7, media.TargetDuration)
+	assert.Equal(t, 364042169, media.MediaSequence)
+	assert.Equal(t, 0, media.DiscontinuitySequence)
+	assert.Equal(t, "", media.PlaylistType)
+	assert.Len(t, media.Segments, len(playlist.Segments()))
+
+	segment := media.Segments[0]
+	assert.Equal(t, playlist.Segments()[0], segment.Node)
+	assert.Equal(t, "channel-audio_1=96000-video=3442944-364042169.ts", segment.URI)
+	assert.Equal(t, 4.8, segment.Duration)
+	assert.Equal(t, " no desc", segment.Title)
+	assert.Equal(t, 364042169, segment.MediaSequence)
+	assert.Equal(t, "2025-05-16T13:33:27.966666Z", segment.ProgramDateTime.Format("2006-01-02T15:04:05.999999Z07:00"))
+	assert.False(t, segment.Gap)
+
+	// edits through the view change the nodes
+	media.SetTargetDuration(8)
+	media.SetMediaSequence(100)
+	media.SetPlaylistType("EVENT")
+	media.Segments[0].SetURI("segment-100.ts")
+	media.Segments[0].SetDuration(6)
+
+	assert.Equal(t, 100, media.Segments[0].MediaSequence)
+	assert.Equal(t, 101, media.Segments[1].MediaSequence)
+	assert.Equal(t, "101", playlist.Segments()[1].HLSElement.Details["MediaSequence"])
+	assert.Equal(t, 100, playlist.MediaSequence)
+	assert.Equal(t, pl.RoundFloat(dvr+1.2, 4), playlist.DVR)
+
+	p, err := m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Contains(t, p, "#EXT-X-TARGETDURATION:8\n")
+	assert.Contains(t, p, "#EXT-X-MEDIA-SEQUENCE:100\n")
+	assert.Contains(t, p, "#EXT-X-PLAYLIST-TYPE:EVENT\n")
+	assert.Contains(t, p, "#EXTINF:6, no desc\nsegment-100.ts\n")
+
+	// missing tags are inserted along with the other playlist tags
+	manifest := "#EXTM3U\n#EXT-X-VERSION:3\n#EXTINF:6,\nsegment1.ts"
+	playlist, err = m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest)))
+	assert.NoError(t, err)
+	media, err = playlist.MediaPlaylist()
+	assert.NoError(t, err)
+	media.SetTargetDuration(6)
+	assert.Equal(t, "TargetDuration", playlist.Head.Next.Next.HLSElement.Name)
+
+	// multivariant playlists have no media playlist view
+	file, _ = os.Open("./../mocks/multivariant/multivariant.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+	_, err = playlist.MediaPlaylist()
+	assert.Error(t, err)
+}
+
+func TestMultivariantPlaylist(t *testing.T) {
+	file, _ := os.Open("./../mocks/multivariant/withAudioGroups.m3u8")
+	playlist, err := m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+
+	multivariant, err := playlist.MultivariantPlaylist()
+	assert.NoError(t, err)
+	assert.Len(t, multivariant.Variants, len(playlist.Variants()))
+	assert.Len(t, multivariant.Renditions, 3)
+	assert.Len(t, multivariant.IFrameVariants, 4)
+
+	variant := multivariant.Variants[0]
+	assert.Equal(t, playlist.Variants()[0], variant.Node)
+	assert.Equal(t, "channel-video=1476992.m3u8?dvr_window_length=120", variant.URI)
+	assert.Equal(t, 1829000, variant.Bandwidth)
+	assert.Equal(t, 1663000, variant.AverageBandwidth)
+	assert.Equal(t, []string{"mp4a.40.2", "avc1.64001F"}, variant.Codecs)
+	assert.Equal(t, "1280x720", variant.Resolution)
+	assert.Equal(t, 30.0, variant.FrameRate)
+	assert.Equal(t, "audio-aacl-96", variant.Audio)
+	assert.Equal(t, "NONE", variant.ClosedCaptions)
+
+	rendition := multivariant.Renditions[0]
+	assert.Equal(t, "AUDIO", rendition.Type)
+	assert.Equal(t, "audio-aacl-96", rendition.GroupID)
+	assert.Equal(t, "qaa", rendition.Language)
+	assert.True(t, rendition.Default)
+	assert.True(t, rendition.Autoselect)
+
+	iFrameVariant := multivariant.IFrameVariants[0]
+	assert.Equal(t, 82000, iFrameVariant.Bandwidth)
+	assert.Equal(t, "640x360", iFrameVariant.Resolution)
+	assert.Equal(t, "keyframes/channel-video=558976.m3u8?dvr_window_length=120", iFrameVariant.URI)
+
+	// edits through the view change the nodes
+	multivariant.Variants[0].SetURI("https://cdn.example.com/720p.m3u8")
+	multivariant.Variants[0].SetBandwidth(2000000)
+	multivariant.Variants[0].SetAverageBandwidth(0)
+	multivariant.Renditions[0].SetLanguage("pt")
+	multivariant.IFrameVariants[0].SetURI("https://cdn.example.com/keyframes.m3u8")
+
+	p, err := m3u8.EncodePlaylist(playlist)
+	assert.NoError(t, err)
+	assert.Contains(t, p, "#EXT-X-STREAM-INF:BANDWIDTH=2000000,CODECS=\"mp4a.40.2,avc1.64001F\",RESOLUTION=1280x720,FRAME-RATE=30,AUDIO=\"audio-aacl-96\",CLOSED-CAPTIONS=NONE\nhttps://cdn.example.com/720p.m3u8\n")
+	assert.Contains(t, p, `LANGUAGE="pt"`)
+	assert.Contains(t, p, `URI="https://cdn.example.com/keyframes.m3u8"`)
+
+	// media playlists have no multivariant playlist view
+	file, _ = os.Open("./../mocks/media/media.m3u8")
+	playlist, err = m3u8.ParsePlaylist(file)
+	assert.NoError(t, err)
+	_, err = playlist.MultivariantPlaylist()
+	assert.Error(t, err)
+}
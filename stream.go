@@ -0,0 +1,164 @@
+package go_m3u8
+
+import (
+	"bufio"
+	"iter"
+	"time"
+
+	"github.com/globocom/go-m3u8/node"
+	pl "github.com/globocom/go-m3u8/playlist"
+	"github.com/globocom/go-m3u8/tags"
+)
+
+// Reads an m3u8 playlist from the provided source, using the Decoder's tag parsers, and returns an iterator over its
+// HLS elements. See StreamPlaylist.
+func (d *Decoder) Stream(src Source, opts ...ParseOption) iter.Seq2[*node.HLSElement, error] {
+	return streamPlaylist(src, d.parsers, opts)
+}
+
+// Reads an m3u8 playlist from the provided source and returns an iterator over its HLS elements, in playlist order,
+// without building the whole Playlist in memory. It is meant for very large playlists (e.g. long VOD or EVENT playlists).
+//
+// Elements are parsed by the same parsers as ParsePlaylist, so each one holds the same Attrs and Details
+// (e.g. the MediaSequence and ProgramDateTime of each segment). Once yielded, an element is dropped from the
+// playlist state, except for the playlist-level tags and the ones the parsers still depend on: the last segment and
+// the elements after it, and the Ad Breaks whose first segment is not known yet. Of the segments after such an Ad Break,
+// only the ones that may be its first segment are kept (see adBreakStart), so memory use does not grow with the playlist.
+// The Details of such Ad Break DateRange (#EXT-X-DATERANGE) elements are updated in place when the playlist ends,
+// as they are by ParsePlaylist.
+//
+// Parsing stops at the first error, which is yielded along with a nil element. In lenient mode (see WithLenient),
+// invalid lines do not stop parsing, and the DiagnosticsError is yielded after the last element. The source is closed once the
+// iteration ends, either because the source was fully read or because the loop was stopped.
+func StreamPlaylist(src Source, opts ...ParseOption) iter.Seq2[*node.HLSElement, error] {
+	return streamPlaylist(src, tags.Parsers, opts)
+}
+
+func streamPlaylist(src Source, parsers map[string]tags.TagParser, opts []ParseOption) iter.Seq2[*node.HLSElement, error] {
+	return func(yield func(*node.HLSElement, error) bool) {
+		decoder := newLineDecoder(parsers, opts)
+		breakStarts := make(map[*node.Node]*adBreakStart)
+
+		scanner := bufio.NewScanner(src)
+		defer closeSource(src)
+
+		for scanner.Scan() {
+			tail := decoder.playlist.Tail
+			if err := decoder.decodeLine(scanner.Text()); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			// nodes are always appended, so the new ones are the ones after the previous tail
+			next := decoder.playlist.Head
+			if tail != nil {
+				next = tail.Next
+			}
+			for current := next; current != nil; current = current.Next {
+				if !yield(current.HLSElement, nil) {
+					return
+				}
+			}
+
+			dropStreamedNodes(decoder.playlist, breakStarts)
+		}
+		if err := decoder.scanError(scanner.Err()); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		decoder.finish()
+		if err := decoder.diagnosticsError(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// The segments kept by the streaming parser for a pending Ad Break, as its first segment is either the last segment
+// that starts before the Break's start date (within the allowed difference) or the first one that does not.
+type adBreakStart struct {
+	before *node.Node
+	after  *node.Node
+}
+
+// Returns true if the segment is kept for any of the pending Ad Breaks.
+func isBreakStart(breakStarts map[*node.Node]*adBreakStart, segment *node.Node) bool {
+	for _, start := range breakStarts {
+		if start.before == segment || start.after == segment {
+			return true
+		}
+	}
+	return false
+}
+
+// Removes the already yielded nodes that are no longer needed to parse the rest of the playlist.
+// The playlist counters (e.g. SegmentsCounter, DVR) are not changed, so the following elements are parsed as if
+// the removed nodes were still there. The segments that may be the first segment of a pending Ad Break before them
+// are kept, so the Break is resolved when the playlist ends.
+func dropStreamedNodes(playlist *pl.Playlist, breakStarts map[*node.Node]*adBreakStart) {
+	var lastSegment *node.Node
+	for current := playlist.Tail; current != nil; current = current.Prev {
+		if current.HLSElement.Name == tags.ExtInfName {
+			lastSegment = current
+			break
+		}
+	}
+
+	pendingBreaks := make([]*node.Node, 0)
+	replaced := make([]*node.Node, 0)
+	for current := playlist.Head; current != nil && current != lastSegment; {
+		next := current.Next
+		switch {
+		case pl.IsPlaylistLevel(current.HLSElement.Name):
+		case isPendingBreak(current):
+			pendingBreaks = append(pendingBreaks, current)
+			if _, exists := breakStarts[current]; !exists {
+				breakStarts[current] = &adBreakStart{}
+			}
+		case current.HLSElement.Name == tags.ExtInfName:
+			if isBreakStart(breakStarts, current) {
+				break
+			}
+
+			segmentPDT, _ := time.Parse(time.RFC3339Nano, current.HLSElement.Details["ProgramDateTime"])
+			for _, breakNode := range pendingBreaks {
+				start := breakStarts[breakNode]
+				if start.after != nil {
+					continue
+				}
+
+				breakStartDate, _ := time.Parse(time.RFC3339Nano, breakNode.HLSElement.Attrs["START-DATE"])
+				if segmentPDT.IsZero() || !segmentPDT.Before(breakStartDate) {
+					start.after = current
+				} else {
+					if start.before != nil {
+						replaced = append(replaced, start.before)
+					}
+					start.before = current
+				}
+			}
+
+			if !isBreakStart(breakStarts, current) {
+				playlist.DoublyLinkedList.Remove(current)
+			}
+		default:
+			playlist.DoublyLinkedList.Remove(current)
+		}
+		current = next
+	}
+
+	for _, segment := range replaced {
+		if !isBreakStart(breakStarts, segment) {
+			playlist.DoublyLinkedList.Remove(segment)
+		}
+	}
+}
+
+// Returns true if the given node is an Ad Break DateRange (#EXT-X-DATERANGE) whose first segment is not known yet.
+func isPendingBreak(node *node.Node) bool {
+	element := node.HLSElement
+	return element.Name == tags.DateRangeName &&
+		element.Attrs["SCTE35-OUT"] != "" &&
+		element.Details != nil &&
+		element.Details["Status"] != tags.BreakStatusComplete
+}
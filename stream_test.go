@@ -0,0 +1,149 @@
+package go_m3u8_test
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	m3u8 "github.com/globocom/go-m3u8"
+	"github.com/globocom/go-m3u8/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamPlaylist(t *testing.T) {
+	t.Run("Elements match ParsePlaylist for every mock", func(t *testing.T) {
+		err := filepath.WalkDir("mocks", func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || filepath.Ext(path) != ".m3u8" {
+				return err
+			}
+
+			t.Run(path, func(t *testing.T) {
+				file, err := os.Open(path)
+				assert.NoError(t, err)
+				p, parseErr := m3u8.ParsePlaylist(file)
+
+				file, err = os.Open(path)
+				assert.NoError(t, err)
+				elements := make([]*internal.HLSElement, 0)
+				var streamErr error
+				for element, err := range m3u8.StreamPlaylist(file) {
+					if err != nil {
+						streamErr = err
+						break
+					}
+					elements = append(elements, element)
+				}
+
+				if parseErr != nil {
+					assert.EqualError(t, streamErr, parseErr.Error())
+					return
+				}
+				assert.NoError(t, streamErr)
+
+				i := 0
+				for current := p.Head; current != nil; current = current.Next {
+					if !assert.Less(t, i, len(elements)) {
+						return
+					}
+					assert.Equal(t, current.HLSElement.Name, elements[i].Name)
+					assert.Equal(t, current.HLSElement.URI, elements[i].URI)
+					assert.Equal(t, current.HLSElement.Attrs, elements[i].Attrs)
+					assert.Equal(t, current.HLSElement.Details, elements[i].Details)
+					i++
+				}
+				assert.Equal(t, i, len(elements))
+			})
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Ad Breaks are resolved without keeping their segments", func(t *testing.T) {
+		// the second start date is a few milliseconds after the start of the Break's first segment
+		for _, test := range []struct{ endlist, startDate string }{
+			{"#EXT-X-ENDLIST\n", "2025-07-01T10:00:10Z"},
+			{"#EXT-X-ENDLIST\n", "2025-07-01T10:00:10.010Z"},
+			{"", "2025-07-01T10:00:10Z"},
+		} {
+			var manifest strings.Builder
+			manifest.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:2\n#EXT-X-MEDIA-SEQUENCE:100\n")
+			manifest.WriteString("#EXT-X-PROGRAM-DATE-TIME:2025-07-01T10:00:00Z\n#EXTINF:2,\nsegment-100.ts\n")
+			manifest.WriteString("#EXT-X-DATERANGE:ID=\"break\",START-DATE=\"" + test.startDate + "\",PLANNED-DURATION=20,SCTE35-OUT=0xFC\n")
+			for i := 101; i < 120; i++ {
+				manifest.WriteString("#EXTINF:2,\nsegment-" + strconv.Itoa(i) + ".ts\n")
+			}
+			manifest.WriteString(test.endlist)
+
+			p, err := m3u8.ParsePlaylist(io.NopCloser(strings.NewReader(manifest.String())))
+			assert.NoError(t, err)
+			expected := p.Breaks()[0].HLSElement.Details
+
+			var breakElement *internal.HLSElement
+			for element, err := range m3u8.StreamPlaylist(io.NopCloser(strings.NewReader(manifest.String()))) {
+				assert.NoError(t, err)
+				if element.Name == "DateRange" {
+					breakElement = element
+				}
+			}
+			assert.Equal(t, expected, breakElement.Details)
+			if test.endlist != "" {
+				assert.Equal(t, "105", breakElement.Details["StartMediaSequence"])
+			}
+		}
+	})
+
+	t.Run("Stopping the loop closes the source", func(t *testing.T) {
+		src := &closeTracker{Reader: strings.NewReader("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:2\n#EXTINF:2,\nsegment.ts\n")}
+		names := make([]string, 0)
+		for element, err := range m3u8.StreamPlaylist(src) {
+			assert.NoError(t, err)
+			names = append(names, element.Name)
+			if element.Name == "Version" {
+				break
+			}
+		}
+		assert.Equal(t, []string{"M3u8Identifier", "Version"}, names)
+		assert.True(t, src.closed)
+	})
+
+	t.Run("Errors stop the iteration", func(t *testing.T) {
+		src := io.NopCloser(strings.NewReader("#EXTM3U\n#EXT-X-TARGETDURATION:2\n#EXT-X-START:PRECISE=YES\n#EXTINF:2,\nsegment.ts\n"))
+		count := 0
+		var streamErr error
+		for element, err := range m3u8.StreamPlaylist(src) {
+			if err != nil {
+				assert.Nil(t, element)
+				streamErr = err
+				continue
+			}
+			count++
+		}
+		assert.Error(t, streamErr)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("Decoder streams with its own parsers", func(t *testing.T) {
+		decoder := m3u8.NewDecoder().Register("#EXT-X-CUSTOM", customTagParser{name: "Custom"})
+		src := io.NopCloser(strings.NewReader("#EXTM3U\n#EXT-X-CUSTOM:42\n"))
+		names := make([]string, 0)
+		for element, err := range decoder.Stream(src) {
+			assert.NoError(t, err)
+			names = append(names, element.Name)
+		}
+		assert.Equal(t, []string{"M3u8Identifier", "Custom"}, names)
+	})
+}
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
@@ -5,9 +5,10 @@ package tags
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
-	"github.com/globocom/go-m3u8/internal"
+	"github.com/globocom/go-m3u8/node"
 	pl "github.com/globocom/go-m3u8/playlist"
 )
 
@@ -32,8 +33,8 @@ type (
 )
 
 func (p M3u8IdentifierParser) Parse(tag string, playlist *pl.Playlist) error {
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name: M3u8IdentifierName,
 			Attrs: map[string]string{
 				M3u8IdentifierTag: "",
@@ -46,8 +47,8 @@ func (p M3u8IdentifierParser) Parse(tag string, playlist *pl.Playlist) error {
 func (p VersionParser) Parse(tag string, playlist *pl.Playlist) error {
 	parts := strings.Split(tag, ":")
 	if len(parts) > 1 && parts[1] != "" {
-		playlist.Insert(&internal.Node{
-			HLSElement: &internal.HLSElement{
+		playlist.Insert(&node.Node{
+			HLSElement: &node.HLSElement{
 				Name:  VersionName,
 				Attrs: map[string]string{VersionTag: strings.TrimSpace(parts[1])},
 			},
@@ -57,11 +58,11 @@ func (p VersionParser) Parse(tag string, playlist *pl.Playlist) error {
 	return fmt.Errorf("invalid version tag: %s", tag)
 }
 
-func (e M3u8IdentifierEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	_, err := builder.WriteString(M3u8IdentifierTag + "\n")
+func (e M3u8IdentifierEncoder) Encode(node *node.Node, w io.Writer) error {
+	_, err := io.WriteString(w, M3u8IdentifierTag+"\n")
 	return err
 }
 
-func (e VersionEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	return pl.EncodeSimpleTag(node, builder, VersionTag, VersionTag)
+func (e VersionEncoder) Encode(node *node.Node, w io.Writer) error {
+	return pl.EncodeSimpleTag(node, w, VersionTag, VersionTag)
 }
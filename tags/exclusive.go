@@ -17,36 +17,39 @@ package tags
 
 import (
 	"fmt"
-	"strings"
+	"io"
 
-	"github.com/globocom/go-m3u8/internal"
+	"github.com/globocom/go-m3u8/node"
 	pl "github.com/globocom/go-m3u8/playlist"
 )
 
 const (
 	IndependentSegmentsName = "IndependentSegments"
 	VariableDefineName      = "VariableDefine"
+	StartName               = "Start"
 )
 
 var (
 	IndependentSegmentsTag = "#EXT-X-INDEPENDENT-SEGMENTS"
 	VariableDefineTag      = "#EXT-X-DEFINE"
-	StartTag               = "#EXT-X-START" //todo: has attributes
+	StartTag               = "#EXT-X-START"
 )
 
 type (
 	IndependentSegmentsParser struct{}
 	VariableDefineParser      struct{}
+	StartParser               struct{}
 )
 
 type (
 	IndependentSegmentsEncoder struct{}
 	VariableDefineEncoder      struct{}
+	StartEncoder               struct{}
 )
 
 func (p IndependentSegmentsParser) Parse(tag string, playlist *pl.Playlist) error {
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name: IndependentSegmentsName,
 			Attrs: map[string]string{
 				IndependentSegmentsTag: "",
@@ -67,8 +70,8 @@ func (p VariableDefineParser) Parse(tag string, playlist *pl.Playlist) error {
 		return fmt.Errorf("VALUE attribute is required for NAME attribute: %s", tag)
 	}
 
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name:  VariableDefineName,
 			Attrs: params,
 		},
@@ -77,12 +80,45 @@ func (p VariableDefineParser) Parse(tag string, playlist *pl.Playlist) error {
 	return nil
 }
 
-func (e IndependentSegmentsEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	_, err := builder.WriteString(IndependentSegmentsTag + "\n")
+// #EXT-X-START:<attribute-list>
+//
+// TIME-OFFSET is REQUIRED and PRECISE, when present, MUST be YES or NO.
+func (p StartParser) Parse(tag string, playlist *pl.Playlist) error {
+	params := pl.TagsToMap(tag)
+	if len(params) < 1 {
+		return fmt.Errorf("invalid start tag: %s", tag)
+	}
+
+	// TIME-OFFSET attribute is REQUIRED by RFC
+	if params["TIME-OFFSET"] == "" {
+		return fmt.Errorf("TIME-OFFSET attribute is required: %s", tag)
+	}
+
+	if _, err := pl.GetStartData(params); err != nil {
+		return fmt.Errorf("invalid start tag: %w", err)
+	}
+
+	// The Start tag MUST NOT appear more than once in a Playlist
+	if _, found := playlist.StartTag(); found {
+		return fmt.Errorf("start tag must not appear more than once: %s", tag)
+	}
+
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
+			Name:  StartName,
+			Attrs: params,
+		},
+	})
+
+	return nil
+}
+
+func (e IndependentSegmentsEncoder) Encode(node *node.Node, w io.Writer) error {
+	_, err := io.WriteString(w, IndependentSegmentsTag+"\n")
 	return err
 }
 
-func (e VariableDefineEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
+func (e VariableDefineEncoder) Encode(node *node.Node, w io.Writer) error {
 	orderAttr := []string{"NAME", "VALUE", "IMPORT", "QUERYPARAM"}
 	shouldQuoteAttr := map[string]bool{
 		"NAME":       true,
@@ -90,5 +126,14 @@ func (e VariableDefineEncoder) Encode(node *internal.Node, builder *strings.Buil
 		"IMPORT":     true,
 		"QUERYPARAM": true,
 	}
-	return pl.EncodeTagWithAttributes(builder, VariableDefineTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+	return pl.EncodeTagWithAttributes(w, VariableDefineTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+}
+
+func (e StartEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{"TIME-OFFSET", "PRECISE"}
+	shouldQuoteAttr := map[string]bool{
+		"TIME-OFFSET": false,
+		"PRECISE":     false,
+	}
+	return pl.EncodeTagWithAttributes(w, StartTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
 }
@@ -9,10 +9,11 @@ package tags
 
 import (
 	"fmt"
-	"strings"
+	"io"
+	"strconv"
 	"time"
 
-	"github.com/globocom/go-m3u8/internal"
+	"github.com/globocom/go-m3u8/node"
 	pl "github.com/globocom/go-m3u8/playlist"
 	"github.com/rs/zerolog/log"
 )
@@ -22,19 +23,32 @@ const (
 	BreakStatusNotReady   = "segmentsNotReady"
 	BreakStatusComplete   = "complete"
 	DateRangeName         = "DateRange"
+	PreloadHintName       = "PreloadHint"
+	SkipName              = "Skip"
+	RenditionReportName   = "RenditionReport"
 	breakNotReadyLimit    = 20 * time.Millisecond
 )
 
 var (
 	DateRangeTag       = "#EXT-X-DATERANGE"
-	SkipTag            = "#EXT-X-SKIP"             //todo: has attributes
-	PreLoadHintTag     = "#EXT-X-PRELOAD-HINT"     //todo: has attributes
-	RenditionReportTag = "#EXT-X-RENDITION-REPORT" //todo: has attributes
+	SkipTag            = "#EXT-X-SKIP"
+	PreLoadHintTag     = "#EXT-X-PRELOAD-HINT"
+	RenditionReportTag = "#EXT-X-RENDITION-REPORT"
 )
 
-type DateRangeParser struct{}
+type (
+	DateRangeParser       struct{}
+	PreloadHintParser     struct{}
+	SkipParser            struct{}
+	RenditionReportParser struct{}
+)
 
-type DateRangeEncoder struct{}
+type (
+	DateRangeEncoder       struct{}
+	PreloadHintEncoder     struct{}
+	SkipEncoder            struct{}
+	RenditionReportEncoder struct{}
+)
 
 func (p DateRangeParser) Parse(tag string, playlist *pl.Playlist) error {
 	params := pl.TagsToMap(tag)
@@ -42,8 +56,8 @@ func (p DateRangeParser) Parse(tag string, playlist *pl.Playlist) error {
 		return fmt.Errorf("invalid date range tag: %s", tag)
 	}
 
-	dateRangeNode := &internal.Node{
-		HLSElement: &internal.HLSElement{
+	dateRangeNode := &node.Node{
+		HLSElement: &node.HLSElement{
 			Name:  DateRangeName,
 			Attrs: params,
 		},
@@ -62,7 +76,7 @@ func (p DateRangeParser) Parse(tag string, playlist *pl.Playlist) error {
 	return nil
 }
 
-func (e DateRangeEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
+func (e DateRangeEncoder) Encode(node *node.Node, w io.Writer) error {
 	// Attribute X-<client-attribute> is a client-specific attribute and new ones must be added manually below (e.g., X-ASSET-URI)
 	orderAttr := []string{"ID", "CLASS", "START-DATE", "END-DATE", "DURATION", "PLANNED-DURATION", "X-ASSET-URI", "SCTE35-OUT", "SCTE35-IN"}
 	shouldQuoteAttr := map[string]bool{
@@ -76,28 +90,148 @@ func (e DateRangeEncoder) Encode(node *internal.Node, builder *strings.Builder)
 		"SCTE35-OUT":       false,
 		"SCTE35-IN":        false,
 	}
-	return pl.EncodeTagWithAttributes(builder, DateRangeTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+	return pl.EncodeTagWithAttributes(w, DateRangeTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+}
+
+// #EXT-X-PRELOAD-HINT:<attribute-list>
+//
+// The TYPE (PART or MAP) and URI attributes are REQUIRED.
+func (p PreloadHintParser) Parse(tag string, playlist *pl.Playlist) error {
+	params := pl.TagsToMap(tag)
+	if len(params) < 1 {
+		return fmt.Errorf("invalid preload hint tag: %s", tag)
+	}
+
+	// Valid strings for TYPE are PART and MAP
+	if params["TYPE"] != "PART" && params["TYPE"] != "MAP" {
+		return fmt.Errorf("invalid TYPE attribute value: %s", params["TYPE"])
+	}
+
+	// URI attribute is REQUIRED by RFC
+	if params["URI"] == "" {
+		return fmt.Errorf("URI attribute is required: %s", tag)
+	}
+
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
+			Name:  PreloadHintName,
+			Attrs: params,
+		},
+	})
+
+	return nil
+}
+
+func (e PreloadHintEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{"TYPE", "URI", "BYTERANGE-START", "BYTERANGE-LENGTH"}
+	shouldQuoteAttr := map[string]bool{
+		"TYPE":             false,
+		"URI":              true,
+		"BYTERANGE-START":  false,
+		"BYTERANGE-LENGTH": false,
+	}
+	return pl.EncodeTagWithAttributes(w, PreLoadHintTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+}
+
+// #EXT-X-SKIP:<attribute-list>
+//
+// The Skip tag replaces the segments of a Playlist Delta Update that are older than the Skip Boundary.
+// The skipped segments are accounted for, so the media sequence of the following segments is preserved.
+func (p SkipParser) Parse(tag string, playlist *pl.Playlist) error {
+	params := pl.TagsToMap(tag)
+
+	// SKIPPED-SEGMENTS attribute is REQUIRED by RFC
+	skippedSegments, err := strconv.Atoi(params["SKIPPED-SEGMENTS"])
+	if err != nil || skippedSegments < 0 {
+		return fmt.Errorf("invalid SKIPPED-SEGMENTS attribute: %s", tag)
+	}
+
+	// The Skip tag MUST NOT appear more than once in a Playlist
+	if _, found := playlist.SkipTag(); found {
+		return fmt.Errorf("skip tag must not appear more than once: %s", tag)
+	}
+
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
+			Name:  SkipName,
+			Attrs: params,
+		},
+	})
+
+	playlist.SegmentsCounter += skippedSegments
+	return nil
+}
+
+func (e SkipEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{"SKIPPED-SEGMENTS", "RECENTLY-REMOVED-DATERANGES"}
+	shouldQuoteAttr := map[string]bool{
+		"SKIPPED-SEGMENTS":            false,
+		"RECENTLY-REMOVED-DATERANGES": true,
+	}
+	return pl.EncodeTagWithAttributes(w, SkipTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+}
+
+// #EXT-X-RENDITION-REPORT:<attribute-list>
+//
+// The Rendition Report tag carries information about the last segment and part of an associated Rendition.
+func (p RenditionReportParser) Parse(tag string, playlist *pl.Playlist) error {
+	params := pl.TagsToMap(tag)
+
+	// URI attribute is REQUIRED by RFC
+	if params["URI"] == "" {
+		return fmt.Errorf("URI attribute is required: %s", tag)
+	}
+
+	for _, key := range []string{"LAST-MSN", "LAST-PART"} {
+		if value, exists := params[key]; exists {
+			if _, err := strconv.Atoi(value); err != nil {
+				return fmt.Errorf("invalid %s attribute value: %s", key, value)
+			}
+		}
+	}
+
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
+			Name:  RenditionReportName,
+			Attrs: params,
+		},
+	})
+
+	return nil
+}
+
+func (e RenditionReportEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{"URI", "LAST-MSN", "LAST-PART"}
+	shouldQuoteAttr := map[string]bool{
+		"URI":       true,
+		"LAST-MSN":  false,
+		"LAST-PART": false,
+	}
+	return pl.EncodeTagWithAttributes(w, RenditionReportTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
 }
 
 // Returns the Ad Break's media sequence (string) and status (string).
 //   - The Break's media sequence will be the media sequence of the first segment inside the break (or zero if Break is incomplete).
 //   - The Break's status will be: "complete" or incomplete ("leavingDVRLimit" or "segmentsNotReady").
-func getAdBreakDetails(playlist *pl.Playlist, dateRangeNode *internal.Node) (value, status string) {
+func getAdBreakDetails(playlist *pl.Playlist, dateRangeNode *node.Node) (value, status string) {
 	currentMediaSequence := fmt.Sprintf("%d", playlist.MediaSequence+playlist.SegmentsCounter)
 	breakStartDate, _ := time.Parse(time.RFC3339Nano, dateRangeNode.HLSElement.Attrs["START-DATE"])
 
 	// when ad break segments are leaving DVR, we lose the break's first segment's media sequence
-	if playlist.ProgramDateTime.IsZero() {
-		// if the playlist's PDT tag was not parsed yet, we check if there are any media segments before the date range tag
-		if len(playlist.Segments()) == 0 {
-			log.Debug().Str("service", "go-m3u8/tags/media/metadata.go").Msg("ad break leaving dvr limit")
-			return "0", BreakStatusLeavingDVR
-		}
-	} else {
-		// if the playlist's PDT tag was already parsed, we check if the playlist PDT is equal or higher than the break's start date
-		if playlist.ProgramDateTime.Equal(breakStartDate) || playlist.ProgramDateTime.After(breakStartDate) {
-			log.Debug().Str("service", "go-m3u8/tags/media/metadata.go").Msg("ad break leaving dvr limit")
-			return "0", BreakStatusLeavingDVR
+	// segments only leave the DVR when the playlist is a sliding live window (i.e. not a VOD or EVENT playlist)
+	if playlist.IsLive() {
+		if playlist.ProgramDateTime.IsZero() {
+			// if the playlist's PDT tag was not parsed yet, we check if there are any media segments before the date range tag
+			if len(playlist.Segments()) == 0 {
+				log.Debug().Str("service", "go-m3u8/tags/media/metadata.go").Msg("ad break leaving dvr limit")
+				return "0", BreakStatusLeavingDVR
+			}
+		} else {
+			// if the playlist's PDT tag was already parsed, we check if the playlist PDT is equal or higher than the break's start date
+			if playlist.ProgramDateTime.Equal(breakStartDate) || playlist.ProgramDateTime.After(breakStartDate) {
+				log.Debug().Str("service", "go-m3u8/tags/media/metadata.go").Msg("ad break leaving dvr limit")
+				return "0", BreakStatusLeavingDVR
+			}
 		}
 	}
 
@@ -117,3 +251,36 @@ func getAdBreakDetails(playlist *pl.Playlist, dateRangeNode *internal.Node) (val
 
 	return currentMediaSequence, BreakStatusComplete
 }
+
+// Resolves incomplete Ad Breaks once the playlist has ended (#EXT-X-ENDLIST).
+// Since no segments will be added or removed anymore, the Break's first segment is the first
+// segment after the DateRange tag whose PDT is not before the Break's start date.
+// As when parsing live playlists, its PDT must match the Break's start date, within breakNotReadyLimit.
+func resolveEndedAdBreaks(playlist *pl.Playlist) {
+	for _, breakNode := range playlist.Breaks() {
+		details := breakNode.HLSElement.Details
+		if details == nil || details["Status"] == BreakStatusComplete {
+			continue
+		}
+
+		breakStartDate, _ := time.Parse(time.RFC3339Nano, breakNode.HLSElement.Attrs["START-DATE"])
+		for current := breakNode.Next; current != nil; current = current.Next {
+			if current.HLSElement.Name != ExtInfName {
+				continue
+			}
+
+			segmentPDT, _ := time.Parse(time.RFC3339Nano, current.HLSElement.Details["ProgramDateTime"])
+			if !segmentPDT.IsZero() && breakStartDate.Sub(segmentPDT) > breakNotReadyLimit {
+				continue
+			}
+
+			// the Break's first segment may have left the playlist already, in which case the first remaining segment
+			// starts long after the Break's start date and the Break is left incomplete
+			if segmentPDT.IsZero() || segmentPDT.Sub(breakStartDate) <= breakNotReadyLimit {
+				details["StartMediaSequence"] = current.HLSElement.Details["MediaSequence"]
+				details["Status"] = BreakStatusComplete
+			}
+			break
+		}
+	}
+}
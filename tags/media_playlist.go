@@ -9,10 +9,11 @@ package tags
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
-	"github.com/globocom/go-m3u8/internal"
+	"github.com/globocom/go-m3u8/node"
 	pl "github.com/globocom/go-m3u8/playlist"
 	"github.com/rs/zerolog/log"
 )
@@ -22,6 +23,12 @@ const (
 	MediaSequenceName         = "MediaSequence"
 	DiscontinuitySequenceName = "DiscontinuitySequence"
 	IFramesOnlyName           = "IFramesOnly"
+	EndlistName               = "Endlist"
+	PlaylistTypeName          = "PlaylistType"
+	PlaylistTypeVOD           = "VOD"
+	PlaylistTypeEvent         = "EVENT"
+	PartInfName               = "PartInf"
+	ServerControlName         = "ServerControl"
 )
 
 var (
@@ -29,10 +36,10 @@ var (
 	MediaSequenceTag         = "#EXT-X-MEDIA-SEQUENCE"
 	DiscontinuitySequenceTag = "#EXT-X-DISCONTINUITY-SEQUENCE"
 	IFramesOnlyTag           = "#EXT-X-I-FRAMES-ONLY"
-	EndlistTag               = "#EXT-X-ENDLIST"        // todo
-	PlaylistTypeTag          = "#EXT-X-PLAYLIST-TYPE"  // todo: has one attribute
-	PartInfTag               = "#EXT-X-PART-INF"       // todo: has attributes
-	ServerControlTag         = "#EXT-X-SERVER-CONTROL" // todo: has attributes
+	EndlistTag               = "#EXT-X-ENDLIST"
+	PlaylistTypeTag          = "#EXT-X-PLAYLIST-TYPE"
+	PartInfTag               = "#EXT-X-PART-INF"
+	ServerControlTag         = "#EXT-X-SERVER-CONTROL"
 )
 
 type (
@@ -40,6 +47,10 @@ type (
 	MediaSequenceParser         struct{}
 	DiscontinuitySequenceParser struct{}
 	IFramesOnlyParser           struct{}
+	EndlistParser               struct{}
+	PlaylistTypeParser          struct{}
+	PartInfParser               struct{}
+	ServerControlParser         struct{}
 )
 
 type (
@@ -47,13 +58,17 @@ type (
 	MediaSequenceEncoder         struct{}
 	DiscontinuitySequenceEncoder struct{}
 	IFramesOnlyEncoder           struct{}
+	EndlistEncoder               struct{}
+	PlaylistTypeEncoder          struct{}
+	PartInfEncoder               struct{}
+	ServerControlEncoder         struct{}
 )
 
 func (p TargetDurationParser) Parse(tag string, playlist *pl.Playlist) error {
 	parts := strings.Split(tag, ":")
 	if len(parts) > 1 && parts[1] != "" {
-		playlist.Insert(&internal.Node{
-			HLSElement: &internal.HLSElement{
+		playlist.Insert(&node.Node{
+			HLSElement: &node.HLSElement{
 				Name:  TargetDurationName,
 				Attrs: map[string]string{TargetDurationTag: strings.TrimSpace(parts[1])},
 			},
@@ -68,8 +83,8 @@ func (p MediaSequenceParser) Parse(tag string, playlist *pl.Playlist) error {
 	parts := strings.Split(tag, ":")
 	if len(parts) > 1 && parts[1] != "" {
 		mediaSequence := strings.TrimSpace(parts[1])
-		playlist.Insert(&internal.Node{
-			HLSElement: &internal.HLSElement{
+		playlist.Insert(&node.Node{
+			HLSElement: &node.HLSElement{
 				Name:  MediaSequenceName,
 				Attrs: map[string]string{MediaSequenceTag: mediaSequence},
 			},
@@ -91,8 +106,8 @@ func (p DiscontinuitySequenceParser) Parse(tag string, playlist *pl.Playlist) er
 	parts := strings.Split(tag, ":")
 	if len(parts) > 1 && parts[1] != "" {
 		discontinuitySequence := strings.TrimSpace(parts[1])
-		playlist.Insert(&internal.Node{
-			HLSElement: &internal.HLSElement{
+		playlist.Insert(&node.Node{
+			HLSElement: &node.HLSElement{
 				Name:  DiscontinuitySequenceName,
 				Attrs: map[string]string{DiscontinuitySequenceTag: discontinuitySequence},
 			},
@@ -118,8 +133,8 @@ func (p IFramesOnlyParser) Parse(tag string, playlist *pl.Playlist) error {
 		return fmt.Errorf("use of %s REQUIRES a compatibility version number of 4 or greater, but playlist version is %d", tag, hlsVersion)
 	}
 
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name: IFramesOnlyName,
 			Attrs: map[string]string{
 				IFramesOnlyTag: "",
@@ -129,19 +144,132 @@ func (p IFramesOnlyParser) Parse(tag string, playlist *pl.Playlist) error {
 	return nil
 }
 
-func (e TargetDurationEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	return pl.EncodeSimpleTag(node, builder, TargetDurationTag, TargetDurationTag)
+// #EXT-X-ENDLIST
+//
+// The EXT-X-ENDLIST tag indicates that no more Media Segments will be added to the Media Playlist.
+// Once it is parsed, Ad Breaks that were flagged as incomplete are resolved against the segments that follow them,
+// since there is no sliding live window pushing segments out of the playlist.
+func (p EndlistParser) Parse(tag string, playlist *pl.Playlist) error {
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
+			Name: EndlistName,
+			Attrs: map[string]string{
+				EndlistTag: "",
+			},
+		},
+	})
+
+	resolveEndedAdBreaks(playlist)
+	return nil
+}
+
+// #EXT-X-PLAYLIST-TYPE:<type-enum>
+//
+// The value is an enumerated-string; valid strings are EVENT and VOD.
+func (p PlaylistTypeParser) Parse(tag string, playlist *pl.Playlist) error {
+	parts := strings.Split(tag, ":")
+	if len(parts) > 1 && parts[1] != "" {
+		playlistType := strings.TrimSpace(parts[1])
+		if playlistType != PlaylistTypeVOD && playlistType != PlaylistTypeEvent {
+			return fmt.Errorf("invalid playlist type value: %s", playlistType)
+		}
+
+		playlist.Insert(&node.Node{
+			HLSElement: &node.HLSElement{
+				Name:  PlaylistTypeName,
+				Attrs: map[string]string{PlaylistTypeTag: playlistType},
+			},
+		})
+		return nil
+	}
+	return fmt.Errorf("invalid playlist type tag: %s", tag)
+}
+
+// #EXT-X-PART-INF:<attribute-list>
+//
+// The PART-TARGET attribute is REQUIRED and holds the Part Target Duration in seconds.
+func (p PartInfParser) Parse(tag string, playlist *pl.Playlist) error {
+	params := pl.TagsToMap(tag)
+	if params["PART-TARGET"] == "" {
+		return fmt.Errorf("PART-TARGET attribute is required: %s", tag)
+	}
+
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
+			Name:  PartInfName,
+			Attrs: params,
+		},
+	})
+	return nil
+}
+
+// #EXT-X-SERVER-CONTROL:<attribute-list>
+//
+// The tag allows the Server to indicate support for Delivery Directives (Blocking Playlist Reload and Playlist Delta Updates).
+func (p ServerControlParser) Parse(tag string, playlist *pl.Playlist) error {
+	params := pl.TagsToMap(tag)
+	if len(params) < 1 {
+		return fmt.Errorf("invalid server control tag: %s", tag)
+	}
+
+	if _, err := pl.GetServerControlData(params); err != nil {
+		return fmt.Errorf("invalid server control tag: %w", err)
+	}
+
+	// CAN-SKIP-DATERANGES attribute MUST NOT be present unless CAN-SKIP-UNTIL is present
+	if params["CAN-SKIP-DATERANGES"] != "" && params["CAN-SKIP-UNTIL"] == "" {
+		return fmt.Errorf("CAN-SKIP-DATERANGES attribute requires CAN-SKIP-UNTIL attribute: %s", tag)
+	}
+
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
+			Name:  ServerControlName,
+			Attrs: params,
+		},
+	})
+	return nil
 }
 
-func (e MediaSequenceEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	return pl.EncodeSimpleTag(node, builder, MediaSequenceTag, MediaSequenceTag)
+func (e TargetDurationEncoder) Encode(node *node.Node, w io.Writer) error {
+	return pl.EncodeSimpleTag(node, w, TargetDurationTag, TargetDurationTag)
 }
 
-func (e DiscontinuitySequenceEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	return pl.EncodeSimpleTag(node, builder, DiscontinuitySequenceTag, DiscontinuitySequenceTag)
+func (e MediaSequenceEncoder) Encode(node *node.Node, w io.Writer) error {
+	return pl.EncodeSimpleTag(node, w, MediaSequenceTag, MediaSequenceTag)
 }
 
-func (e IFramesOnlyEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	_, err := builder.WriteString(IFramesOnlyTag + "\n")
+func (e DiscontinuitySequenceEncoder) Encode(node *node.Node, w io.Writer) error {
+	return pl.EncodeSimpleTag(node, w, DiscontinuitySequenceTag, DiscontinuitySequenceTag)
+}
+
+func (e IFramesOnlyEncoder) Encode(node *node.Node, w io.Writer) error {
+	_, err := io.WriteString(w, IFramesOnlyTag+"\n")
+	return err
+}
+
+func (e EndlistEncoder) Encode(node *node.Node, w io.Writer) error {
+	_, err := io.WriteString(w, EndlistTag+"\n")
 	return err
 }
+
+func (e PlaylistTypeEncoder) Encode(node *node.Node, w io.Writer) error {
+	return pl.EncodeSimpleTag(node, w, PlaylistTypeTag, PlaylistTypeTag)
+}
+
+func (e PartInfEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{"PART-TARGET"}
+	shouldQuoteAttr := map[string]bool{"PART-TARGET": false}
+	return pl.EncodeTagWithAttributes(w, PartInfTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+}
+
+func (e ServerControlEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{"CAN-SKIP-UNTIL", "CAN-SKIP-DATERANGES", "HOLD-BACK", "PART-HOLD-BACK", "CAN-BLOCK-RELOAD"}
+	shouldQuoteAttr := map[string]bool{
+		"CAN-SKIP-UNTIL":      false,
+		"CAN-SKIP-DATERANGES": false,
+		"HOLD-BACK":           false,
+		"PART-HOLD-BACK":      false,
+		"CAN-BLOCK-RELOAD":    false,
+	}
+	return pl.EncodeTagWithAttributes(w, ServerControlTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+}
@@ -12,10 +12,12 @@ package tags
 
 import (
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/globocom/go-m3u8/internal"
+	"github.com/globocom/go-m3u8/node"
 	pl "github.com/globocom/go-m3u8/playlist"
 )
 
@@ -25,6 +27,7 @@ const (
 	ProgramDateTimeName = "ProgramDateTime"
 	KeyName             = "Key"
 	MapName             = "Map"
+	PartName            = "Part"
 )
 
 var (
@@ -33,9 +36,9 @@ var (
 	ProgramDateTimeTag = "#EXT-X-PROGRAM-DATE-TIME"
 	KeyTag             = "#EXT-X-KEY"
 	MapTag             = "#EXT-X-MAP"
-	ByteRangeTag       = "#EXT-X-BYTERANGE" // todo: has attributes
-	GapTag             = "#EXT-X-GAP"       // todo
-	PartTag            = "#EXT-X-PART"      // todo: has attributes
+	ByteRangeTag       = "#EXT-X-BYTERANGE"
+	GapTag             = "#EXT-X-GAP"
+	PartTag            = "#EXT-X-PART"
 )
 
 type (
@@ -44,6 +47,9 @@ type (
 	ProgramDateTimeParser struct{}
 	KeyParser             struct{}
 	MapParser             struct{}
+	PartParser            struct{}
+	ByteRangeParser       struct{}
+	GapParser             struct{}
 )
 
 type (
@@ -52,6 +58,7 @@ type (
 	ProgramDateTimeEncoder struct{}
 	KeyEncoder             struct{}
 	MapEncoder             struct{}
+	PartEncoder            struct{}
 )
 
 func (p ExtInfParser) Parse(tag string, playlist *pl.Playlist) error {
@@ -66,7 +73,11 @@ func (p ExtInfParser) Parse(tag string, playlist *pl.Playlist) error {
 			title = attrs[1]
 		}
 
-		playlist.CurrentSegment = pl.GetExtInfData(duration, title, playlist.MediaSequence, playlist.SegmentsCounter, playlist.DVR, playlist.ProgramDateTime)
+		segment, err := pl.GetExtInfData(duration, title, playlist.MediaSequence, playlist.SegmentsCounter, playlist.DVR, playlist.ProgramDateTime)
+		if err != nil {
+			return err
+		}
+		playlist.CurrentSegment = segment
 
 		playlist.DVR = pl.RoundFloat(playlist.DVR+playlist.CurrentSegment.Duration, 4)
 		playlist.SegmentsCounter += 1
@@ -77,8 +88,8 @@ func (p ExtInfParser) Parse(tag string, playlist *pl.Playlist) error {
 }
 
 func (p DiscontinuityParser) Parse(tag string, playlist *pl.Playlist) error {
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name: DiscontinuityName,
 			Attrs: map[string]string{
 				DiscontinuityTag: "",
@@ -96,8 +107,8 @@ func (p ProgramDateTimeParser) Parse(tag string, playlist *pl.Playlist) error {
 	}
 
 	dateTimeValue := strings.TrimSpace(parts[1])
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name:  ProgramDateTimeName,
 			Attrs: map[string]string{ProgramDateTimeTag: dateTimeValue},
 		},
@@ -137,8 +148,8 @@ func (p KeyParser) Parse(tag string, playlist *pl.Playlist) error {
 		return fmt.Errorf("IV attribute is required when METHOD is AES-128: %s", tag)
 	}
 
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name:  KeyName,
 			Attrs: params,
 		},
@@ -158,17 +169,100 @@ func (p MapParser) Parse(tag string, playlist *pl.Playlist) error {
 		return fmt.Errorf("URI attribute is required: %s", tag)
 	}
 
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	mapNode := &node.Node{
+		HLSElement: &node.HLSElement{
 			Name:  MapName,
 			Attrs: params,
 		},
+	}
+
+	// BYTERANGE attribute is OPTIONAL, and its offset is zero when not present
+	if params["BYTERANGE"] != "" {
+		byteRange, err := pl.GetByteRangeData(params["BYTERANGE"])
+		if err != nil {
+			return fmt.Errorf("invalid BYTERANGE attribute: %w", err)
+		}
+		mapNode.HLSElement.Details = map[string]string{
+			"ByteRangeLength": strconv.FormatInt(byteRange.Length, 10),
+			"ByteRangeOffset": strconv.FormatInt(byteRange.Offset, 10),
+		}
+	}
+
+	playlist.Insert(mapNode)
+
+	return nil
+}
+
+// #EXT-X-BYTERANGE:<n>[@<o>]
+//
+// The ByteRange tag applies to the next segment, so it is stored until the segment URI is parsed.
+// It is then encoded along with the ExtInf (#EXTINF) tag.
+func (p ByteRangeParser) Parse(tag string, playlist *pl.Playlist) error {
+	parts := strings.SplitN(tag, ":", 2)
+	if len(parts) <= 1 || parts[1] == "" {
+		return fmt.Errorf("invalid byte range tag: %s", tag)
+	}
+
+	byteRange, err := pl.GetByteRangeData(parts[1])
+	if err != nil {
+		return fmt.Errorf("invalid byte range tag: %w", err)
+	}
+
+	playlist.CurrentByteRange = byteRange
+	return nil
+}
+
+// #EXT-X-GAP
+//
+// The Gap tag indicates that the next segment is missing, so it is stored until the segment URI is parsed.
+// It is then encoded along with the ExtInf (#EXTINF) tag.
+func (p GapParser) Parse(tag string, playlist *pl.Playlist) error {
+	playlist.CurrentGap = true
+	return nil
+}
+
+// #EXT-X-PART:<attribute-list>
+//
+// A Partial Segment belongs to the Parent Segment that follows it in the playlist.
+// Its Details hold the media sequence of the Parent Segment and its index inside it.
+func (p PartParser) Parse(tag string, playlist *pl.Playlist) error {
+	params := pl.TagsToMap(tag)
+	if len(params) < 1 {
+		return fmt.Errorf("invalid part tag: %s", tag)
+	}
+
+	// DURATION attribute is REQUIRED by RFC
+	if params["DURATION"] == "" {
+		return fmt.Errorf("DURATION attribute is required: %s", tag)
+	}
+
+	// URI attribute is REQUIRED by RFC
+	if params["URI"] == "" {
+		return fmt.Errorf("URI attribute is required: %s", tag)
+	}
+
+	partIndex := 0
+	for current := playlist.Tail; current != nil && current.HLSElement.Name != ExtInfName; current = current.Prev {
+		if current.HLSElement.Name == PartName {
+			partIndex++
+		}
+	}
+
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
+			Name:  PartName,
+			Attrs: params,
+			Details: map[string]string{
+				"MediaSequence": fmt.Sprintf("%d", playlist.MediaSequence+playlist.SegmentsCounter),
+				"PartIndex":     fmt.Sprintf("%d", partIndex),
+			},
+		},
 	})
 
 	return nil
 }
 
-func (e ExtInfEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
+func (e ExtInfEncoder) Encode(node *node.Node, w io.Writer) error {
 	duration := node.HLSElement.Attrs["Duration"]
 	title := node.HLSElement.Attrs["Title"]
 	uri := node.HLSElement.URI
@@ -178,21 +272,33 @@ func (e ExtInfEncoder) Encode(node *internal.Node, builder *strings.Builder) err
 		title = "," + title
 	}
 
-	attr := fmt.Sprintf("%s:%s%s\n%s\n", ExtInfTag, duration, title, uri)
-	_, err := builder.WriteString(attr)
+	// #EXT-X-BYTERANGE:<n>[@<o>]
+	byteRange := ""
+	if value := node.HLSElement.Attrs["ByteRange"]; value != "" {
+		byteRange = fmt.Sprintf("%s:%s\n", ByteRangeTag, value)
+	}
+
+	// #EXT-X-GAP
+	gap := ""
+	if node.HLSElement.Attrs["Gap"] == "YES" {
+		gap = GapTag + "\n"
+	}
+
+	attr := fmt.Sprintf("%s:%s%s\n%s%s%s\n", ExtInfTag, duration, title, byteRange, gap, uri)
+	_, err := io.WriteString(w, attr)
 	return err
 }
 
-func (e DiscontinuityEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	_, err := builder.WriteString(DiscontinuityTag + "\n")
+func (e DiscontinuityEncoder) Encode(node *node.Node, w io.Writer) error {
+	_, err := io.WriteString(w, DiscontinuityTag+"\n")
 	return err
 }
 
-func (e ProgramDateTimeEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	return pl.EncodeSimpleTag(node, builder, ProgramDateTimeTag, ProgramDateTimeTag)
+func (e ProgramDateTimeEncoder) Encode(node *node.Node, w io.Writer) error {
+	return pl.EncodeSimpleTag(node, w, ProgramDateTimeTag, ProgramDateTimeTag)
 }
 
-func (e KeyEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
+func (e KeyEncoder) Encode(node *node.Node, w io.Writer) error {
 	orderAttr := []string{"METHOD", "URI", "IV", "KEYFORMAT", "KEYFORMATVERSIONS"}
 	shouldQuoteAttr := map[string]bool{
 		"METHOD":            false,
@@ -201,14 +307,26 @@ func (e KeyEncoder) Encode(node *internal.Node, builder *strings.Builder) error
 		"KEYFORMAT":         true,
 		"KEYFORMATVERSIONS": true,
 	}
-	return pl.EncodeTagWithAttributes(builder, KeyTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+	return pl.EncodeTagWithAttributes(w, KeyTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
 }
 
-func (e MapEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
+func (e MapEncoder) Encode(node *node.Node, w io.Writer) error {
 	orderAttr := []string{"URI", "BYTERANGE"}
 	shouldQuoteAttr := map[string]bool{
 		"URI":       true,
 		"BYTERANGE": true,
 	}
-	return pl.EncodeTagWithAttributes(builder, MapTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+	return pl.EncodeTagWithAttributes(w, MapTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+}
+
+func (e PartEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{"DURATION", "URI", "INDEPENDENT", "BYTERANGE", "GAP"}
+	shouldQuoteAttr := map[string]bool{
+		"DURATION":    false,
+		"URI":         true,
+		"INDEPENDENT": false,
+		"BYTERANGE":   true,
+		"GAP":         false,
+	}
+	return pl.EncodeTagWithAttributes(w, PartTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
 }
@@ -11,9 +11,9 @@ package tags
 
 import (
 	"fmt"
-	"strings"
+	"io"
 
-	"github.com/globocom/go-m3u8/internal"
+	"github.com/globocom/go-m3u8/node"
 	pl "github.com/globocom/go-m3u8/playlist"
 )
 
@@ -22,6 +22,8 @@ const (
 	MediaName           = "Media"
 	IFrameStreamInfName = "IFrameStreamInf"
 	SessionKeyName      = "SessionKey"
+	SessionDataName     = "SessionData"
+	ContentSteeringName = "ContentSteering"
 )
 
 var (
@@ -29,6 +31,8 @@ var (
 	MediaTag           = "#EXT-X-MEDIA"
 	IFrameStreamInfTag = "#EXT-X-I-FRAME-STREAM-INF"
 	SessionKeyTag      = "#EXT-X-SESSION-KEY"
+	SessionDataTag     = "#EXT-X-SESSION-DATA"
+	ContentSteeringTag = "#EXT-X-CONTENT-STEERING"
 )
 
 type (
@@ -36,6 +40,8 @@ type (
 	MediaParser           struct{}
 	IFrameStreamInfParser struct{}
 	SessionKeyParser      struct{}
+	SessionDataParser     struct{}
+	ContentSteeringParser struct{}
 )
 
 type (
@@ -43,10 +49,13 @@ type (
 	MediaEncoder           struct{}
 	IFrameStreamInfEncoder struct{}
 	SessionKeyEncoder      struct{}
+	SessionDataEncoder     struct{}
+	ContentSteeringEncoder struct{}
 )
 
 func (p StreamInfParser) Parse(tag string, playlist *pl.Playlist) error {
 	playlist.CurrentStreamInf = pl.GetStreamInfData(pl.TagsToMap(tag))
+	playlist.CurrentStreamInf.QuotedAttrs = pl.QuotedAttributes(tag)
 	return nil
 }
 
@@ -76,8 +85,8 @@ func (p MediaParser) Parse(tag string, playlist *pl.Playlist) error {
 		return fmt.Errorf("URI attribute is not allowed for CLOSED-CAPTIONS type: %s", tag)
 	}
 
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name:  MediaName,
 			Attrs: params,
 		},
@@ -107,8 +116,8 @@ func (p IFrameStreamInfParser) Parse(tag string, playlist *pl.Playlist) error {
 		return fmt.Errorf("URI attribute is required: %s", tag)
 	}
 
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name:  IFrameStreamInfName,
 			Attrs: params,
 		},
@@ -143,8 +152,8 @@ func (p SessionKeyParser) Parse(tag string, playlist *pl.Playlist) error {
 		return fmt.Errorf("IV attribute is required when METHOD is AES-128: %s", tag)
 	}
 
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name:  SessionKeyName,
 			Attrs: params,
 		},
@@ -153,52 +162,139 @@ func (p SessionKeyParser) Parse(tag string, playlist *pl.Playlist) error {
 	return nil
 }
 
-func (e StreamInfEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	orderAttr := []string{"BANDWIDTH", "AVERAGE-BANDWIDTH", "CODECS", "RESOLUTION", "FRAME-RATE", "HDCP-LEVEL", "VIDEO-RANGE", "AUDIO", "VIDEO", "SUBTITLES", "CLOSED-CAPTIONS"}
+func (p SessionDataParser) Parse(tag string, playlist *pl.Playlist) error {
+	params := pl.TagsToMap(tag)
+	if len(params) < 1 {
+		return fmt.Errorf("invalid session data tag: %s", tag)
+	}
+
+	// DATA-ID attribute is REQUIRED by RFC
+	if params["DATA-ID"] == "" {
+		return fmt.Errorf("DATA-ID attribute is required: %s", tag)
+	}
+
+	// Each SessionData tag MUST contain either a VALUE or URI attribute, but not both
+	_, hasValue := params["VALUE"]
+	_, hasURI := params["URI"]
+	if hasValue == hasURI {
+		return fmt.Errorf("either VALUE or URI attribute is required, but not both: %s", tag)
+	}
+
+	// FORMAT attribute is only allowed along with URI, and valid strings are JSON and RAW
+	if format, exists := params["FORMAT"]; exists {
+		if !hasURI {
+			return fmt.Errorf("FORMAT attribute is not allowed without URI attribute: %s", tag)
+		}
+		if format != pl.SessionDataFormatJSON && format != pl.SessionDataFormatRaw {
+			return fmt.Errorf("invalid FORMAT attribute value: %s", format)
+		}
+	}
+
+	// A Playlist MUST NOT contain more than one SessionData tag with the same DATA-ID and LANGUAGE attributes
+	for _, node := range playlist.SessionData() {
+		if node.HLSElement.Attrs["DATA-ID"] == params["DATA-ID"] && node.HLSElement.Attrs["LANGUAGE"] == params["LANGUAGE"] {
+			return fmt.Errorf("session data with the same DATA-ID and LANGUAGE must not appear more than once: %s", tag)
+		}
+	}
+
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
+			Name:  SessionDataName,
+			Attrs: params,
+		},
+	})
+
+	return nil
+}
+
+func (p ContentSteeringParser) Parse(tag string, playlist *pl.Playlist) error {
+	params := pl.TagsToMap(tag)
+	if len(params) < 1 {
+		return fmt.Errorf("invalid content steering tag: %s", tag)
+	}
+
+	// SERVER-URI attribute is REQUIRED by RFC
+	if params["SERVER-URI"] == "" {
+		return fmt.Errorf("SERVER-URI attribute is required: %s", tag)
+	}
+
+	// The ContentSteering tag MUST NOT appear more than once in a Playlist
+	if _, found := playlist.ContentSteering(); found {
+		return fmt.Errorf("content steering tag must not appear more than once: %s", tag)
+	}
+
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
+			Name:  ContentSteeringName,
+			Attrs: params,
+		},
+	})
+
+	return nil
+}
+
+func (e StreamInfEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{
+		"BANDWIDTH", "AVERAGE-BANDWIDTH", "SCORE", "CODECS", "SUPPLEMENTAL-CODECS", "RESOLUTION", "FRAME-RATE", "HDCP-LEVEL",
+		"ALLOWED-CPC", "VIDEO-RANGE", "REQ-VIDEO-LAYOUT", "STABLE-VARIANT-ID", "AUDIO", "VIDEO", "SUBTITLES", "CLOSED-CAPTIONS",
+		"PATHWAY-ID", "PROGRAM-ID",
+	}
 	shouldQuoteAttr := e.shouldQuoteStreamInf(node)
 
-	if err := pl.EncodeTagWithAttributes(builder, StreamInfTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr); err != nil {
+	if err := pl.EncodeTagWithAttributes(w, StreamInfTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr); err != nil {
 		return err
 	}
 	if node.HLSElement.URI != "" {
-		_, err := builder.WriteString(node.HLSElement.URI + "\n")
+		_, err := io.WriteString(w, node.HLSElement.URI+"\n")
 		return err
 	}
 	return nil
 }
 
-func (e MediaEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	orderAttr := []string{"TYPE", "GROUP-ID", "LANGUAGE", "NAME", "DEFAULT", "AUTOSELECT", "CHANNELS", "URI", "INSTREAM-ID"}
+func (e MediaEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{"TYPE", "GROUP-ID", "LANGUAGE", "NAME", "DEFAULT", "AUTOSELECT", "CHANNELS", "URI", "INSTREAM-ID", "STABLE-RENDITION-ID", "PATHWAY-ID"}
 	shouldQuoteAttr := map[string]bool{
-		"TYPE":        false,
-		"GROUP-ID":    true,
-		"LANGUAGE":    true,
-		"NAME":        true,
-		"DEFAULT":     false,
-		"AUTOSELECT":  false,
-		"CHANNELS":    true,
-		"URI":         true,
-		"INSTREAM-ID": true,
-	}
-	return pl.EncodeTagWithAttributes(builder, MediaTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+		"TYPE":                false,
+		"GROUP-ID":            true,
+		"LANGUAGE":            true,
+		"NAME":                true,
+		"DEFAULT":             false,
+		"AUTOSELECT":          false,
+		"CHANNELS":            true,
+		"URI":                 true,
+		"INSTREAM-ID":         true,
+		"STABLE-RENDITION-ID": true,
+		"PATHWAY-ID":          true,
+	}
+	return pl.EncodeTagWithAttributes(w, MediaTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
 }
 
-func (e IFrameStreamInfEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	orderAttr := []string{"BANDWIDTH", "AVERAGE-BANDWIDTH", "CODECS", "RESOLUTION", "URI", "VIDEO-RANGE", "VIDEO", "SCORE"}
+func (e IFrameStreamInfEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{
+		"BANDWIDTH", "AVERAGE-BANDWIDTH", "CODECS", "RESOLUTION", "URI", "VIDEO-RANGE", "VIDEO", "SCORE", "PATHWAY-ID", "STABLE-VARIANT-ID",
+		"SUPPLEMENTAL-CODECS", "HDCP-LEVEL", "ALLOWED-CPC", "REQ-VIDEO-LAYOUT", "PROGRAM-ID",
+	}
 	shouldQuoteAttr := map[string]bool{
-		"BANDWIDTH":         false,
-		"AVERAGE-BANDWIDTH": false,
-		"CODECS":            true,
-		"RESOLUTION":        false,
-		"URI":               true,
-		"VIDEO-RANGE":       false,
-		"VIDEO":             true,
-		"SCORE":             false,
+		"BANDWIDTH":           false,
+		"AVERAGE-BANDWIDTH":   false,
+		"CODECS":              true,
+		"RESOLUTION":          false,
+		"URI":                 true,
+		"VIDEO-RANGE":         false,
+		"VIDEO":               true,
+		"SCORE":               false,
+		"PATHWAY-ID":          true,
+		"STABLE-VARIANT-ID":   true,
+		"SUPPLEMENTAL-CODECS": true,
+		"HDCP-LEVEL":          false,
+		"ALLOWED-CPC":         true,
+		"REQ-VIDEO-LAYOUT":    true,
+		"PROGRAM-ID":          false,
 	}
-	return pl.EncodeTagWithAttributes(builder, IFrameStreamInfTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+	return pl.EncodeTagWithAttributes(w, IFrameStreamInfTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
 }
 
-func (e SessionKeyEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
+func (e SessionKeyEncoder) Encode(node *node.Node, w io.Writer) error {
 	orderAttr := []string{"METHOD", "URI", "IV", "KEYFORMAT", "KEYFORMATVERSIONS"}
 	shouldQuoteAttr := map[string]bool{
 		"METHOD":            false,
@@ -207,22 +303,50 @@ func (e SessionKeyEncoder) Encode(node *internal.Node, builder *strings.Builder)
 		"KEYFORMAT":         true,
 		"KEYFORMATVERSIONS": true,
 	}
-	return pl.EncodeTagWithAttributes(builder, SessionKeyTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+	return pl.EncodeTagWithAttributes(w, SessionKeyTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+}
+
+func (e SessionDataEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{"DATA-ID", "VALUE", "URI", "FORMAT", "LANGUAGE"}
+	shouldQuoteAttr := map[string]bool{
+		"DATA-ID":  true,
+		"VALUE":    true,
+		"URI":      true,
+		"FORMAT":   false,
+		"LANGUAGE": true,
+	}
+	return pl.EncodeTagWithAttributes(w, SessionDataTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
 }
 
-func (e StreamInfEncoder) shouldQuoteStreamInf(node *internal.Node) map[string]bool {
+func (e ContentSteeringEncoder) Encode(node *node.Node, w io.Writer) error {
+	orderAttr := []string{"SERVER-URI", "PATHWAY-ID"}
 	shouldQuoteAttr := map[string]bool{
-		"BANDWIDTH":         false,
-		"AVERAGE-BANDWIDTH": false,
-		"CODECS":            true,
-		"RESOLUTION":        false,
-		"FRAME-RATE":        false,
-		"HDCP-LEVEL":        false,
-		"VIDEO-RANGE":       false,
-		"AUDIO":             true,
-		"VIDEO":             true,
-		"SUBTITLES":         true,
-		"CLOSED-CAPTIONS":   true,
+		"SERVER-URI": true,
+		"PATHWAY-ID": true,
+	}
+	return pl.EncodeTagWithAttributes(w, ContentSteeringTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+}
+
+func (e StreamInfEncoder) shouldQuoteStreamInf(node *node.Node) map[string]bool {
+	shouldQuoteAttr := map[string]bool{
+		"BANDWIDTH":           false,
+		"AVERAGE-BANDWIDTH":   false,
+		"SCORE":               false,
+		"CODECS":              true,
+		"SUPPLEMENTAL-CODECS": true,
+		"RESOLUTION":          false,
+		"FRAME-RATE":          false,
+		"HDCP-LEVEL":          false,
+		"ALLOWED-CPC":         true,
+		"VIDEO-RANGE":         false,
+		"REQ-VIDEO-LAYOUT":    true,
+		"STABLE-VARIANT-ID":   true,
+		"AUDIO":               true,
+		"VIDEO":               true,
+		"SUBTITLES":           true,
+		"CLOSED-CAPTIONS":     true,
+		"PATHWAY-ID":          true,
+		"PROGRAM-ID":          false,
 	}
 
 	// the value can be either a quoted-string or an enumerated-string with the value NONE
@@ -230,5 +354,15 @@ func (e StreamInfEncoder) shouldQuoteStreamInf(node *internal.Node) map[string]b
 		shouldQuoteAttr["CLOSED-CAPTIONS"] = false
 	}
 
+	// other attributes (e.g. client-defined X-<attribute-name>) keep the quoting they were parsed with,
+	// and the ones without it (e.g. added to Attrs only) are quoted-strings
+	for key := range node.HLSElement.Attrs {
+		if _, exists := shouldQuoteAttr[key]; exists {
+			continue
+		}
+		quoted, exists := node.HLSElement.QuotedAttrs[key]
+		shouldQuoteAttr[key] = quoted || !exists
+	}
+
 	return shouldQuoteAttr
 }
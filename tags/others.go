@@ -7,10 +7,11 @@ package tags
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/dlclark/regexp2"
-	"github.com/globocom/go-m3u8/internal"
+	"github.com/globocom/go-m3u8/node"
 	pl "github.com/globocom/go-m3u8/playlist"
 	"github.com/rs/zerolog/log"
 )
@@ -20,6 +21,7 @@ const (
 	EventCueOutName     = "CueOut"
 	EventCueInName      = "CueIn"
 	CommentLineName     = "Comment"
+	UnknownTagName      = "UnknownTag"
 )
 
 var (
@@ -28,6 +30,7 @@ var (
 	EventCueInTag      = "#EXT-X-CUE-IN"
 	CommentLineTag     = "# comment"
 	CommentLineRegex   = regexp2.MustCompile(`^#(?!(EXT|ext|USP)).*`, 0) // excludes tags (#EXT, #ext or #USP)
+	UnknownTagTag      = "# unknown"                                     // any other tag without a registered parser
 )
 
 type (
@@ -35,6 +38,7 @@ type (
 	EventCueOutParser     struct{}
 	EventCueInParser      struct{}
 	CommentParser         struct{}
+	UnknownTagParser      struct{}
 )
 
 type (
@@ -42,14 +46,15 @@ type (
 	EventCueOutEncoder     struct{}
 	EventCueInEncoder      struct{}
 	CommentEncoder         struct{}
+	UnknownTagEncoder      struct{}
 )
 
 func (p USPTimestampMapParser) Parse(tag string, playlist *pl.Playlist) error {
 	parts := strings.SplitN(tag, ":", 2)
 	if len(parts) > 0 {
 		params := pl.TagsToMap(parts[1])
-		playlist.Insert(&internal.Node{
-			HLSElement: &internal.HLSElement{
+		playlist.Insert(&node.Node{
+			HLSElement: &node.HLSElement{
 				Name:  USPTimestampMapName,
 				Attrs: params,
 			},
@@ -69,8 +74,8 @@ func (p EventCueOutParser) Parse(tag string, playlist *pl.Playlist) error {
 		log.Error().Str("service", "go-m3u8/tags/others/others.go").Msgf("invalid cue out tag: %s", tag)
 	}
 
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name:  EventCueOutName,
 			Attrs: map[string]string{EventCueOutTag: duration},
 		},
@@ -80,8 +85,8 @@ func (p EventCueOutParser) Parse(tag string, playlist *pl.Playlist) error {
 }
 
 func (p EventCueInParser) Parse(tag string, playlist *pl.Playlist) error {
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name: EventCueInName,
 			Attrs: map[string]string{
 				EventCueInTag: "",
@@ -93,8 +98,8 @@ func (p EventCueInParser) Parse(tag string, playlist *pl.Playlist) error {
 }
 
 func (p CommentParser) Parse(line string, playlist *pl.Playlist) error {
-	playlist.Insert(&internal.Node{
-		HLSElement: &internal.HLSElement{
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
 			Name: CommentLineName,
 			Attrs: map[string]string{
 				CommentLineName: line,
@@ -105,23 +110,43 @@ func (p CommentParser) Parse(line string, playlist *pl.Playlist) error {
 	return nil
 }
 
-func (e USPTimestampMapEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
+// Tags without a registered parser (e.g. vendor tags) are kept as they are, so that they can be encoded back.
+func (p UnknownTagParser) Parse(line string, playlist *pl.Playlist) error {
+	playlist.Insert(&node.Node{
+		HLSElement: &node.HLSElement{
+			Name: UnknownTagName,
+			Attrs: map[string]string{
+				UnknownTagName: line,
+			},
+		},
+	})
+
+	return nil
+}
+
+func (e USPTimestampMapEncoder) Encode(node *node.Node, w io.Writer) error {
 	orderAttr := []string{"MPEGTS", "LOCAL"}
 	shouldQuoteAttr := map[string]bool{"MPEGTS": false, "LOCAL": false}
-	return pl.EncodeTagWithAttributes(builder, USPTimestampMapTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
+	return pl.EncodeTagWithAttributes(w, USPTimestampMapTag, node.HLSElement.Attrs, orderAttr, shouldQuoteAttr)
 }
 
-func (e EventCueOutEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	return pl.EncodeSimpleTag(node, builder, EventCueOutTag, EventCueOutTag)
+func (e EventCueOutEncoder) Encode(node *node.Node, w io.Writer) error {
+	return pl.EncodeSimpleTag(node, w, EventCueOutTag, EventCueOutTag)
 }
 
-func (e EventCueInEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
-	_, err := builder.WriteString(EventCueInTag + "\n")
+func (e EventCueInEncoder) Encode(node *node.Node, w io.Writer) error {
+	_, err := io.WriteString(w, EventCueInTag+"\n")
 	return err
 }
 
-func (e CommentEncoder) Encode(node *internal.Node, builder *strings.Builder) error {
+func (e CommentEncoder) Encode(node *node.Node, w io.Writer) error {
 	attr := fmt.Sprintf("%s\n", node.HLSElement.Attrs["Comment"])
-	_, err := builder.WriteString(attr)
+	_, err := io.WriteString(w, attr)
+	return err
+}
+
+func (e UnknownTagEncoder) Encode(node *node.Node, w io.Writer) error {
+	attr := fmt.Sprintf("%s\n", node.HLSElement.Attrs[UnknownTagName])
+	_, err := io.WriteString(w, attr)
 	return err
 }
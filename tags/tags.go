@@ -1,9 +1,9 @@
 package tags
 
 import (
-	"strings"
+	"io"
 
-	"github.com/globocom/go-m3u8/internal"
+	"github.com/globocom/go-m3u8/node"
 	pl "github.com/globocom/go-m3u8/playlist"
 )
 
@@ -19,27 +19,41 @@ var Parsers = map[string]TagParser{
 	MediaSequenceTag:         MediaSequenceParser{},
 	DiscontinuitySequenceTag: DiscontinuitySequenceParser{},
 	IFramesOnlyTag:           IFramesOnlyParser{},
+	EndlistTag:               EndlistParser{},
+	PlaylistTypeTag:          PlaylistTypeParser{},
+	PartInfTag:               PartInfParser{},
+	ServerControlTag:         ServerControlParser{},
 	ProgramDateTimeTag:       ProgramDateTimeParser{},
 	KeyTag:                   KeyParser{},
 	MapTag:                   MapParser{},
+	PartTag:                  PartParser{},
+	ByteRangeTag:             ByteRangeParser{},
+	GapTag:                   GapParser{},
 	DateRangeTag:             DateRangeParser{},
+	PreLoadHintTag:           PreloadHintParser{},
+	SkipTag:                  SkipParser{},
+	RenditionReportTag:       RenditionReportParser{},
 	ExtInfTag:                ExtInfParser{},
 	DiscontinuityTag:         DiscontinuityParser{},
 	StreamInfTag:             StreamInfParser{},
 	MediaTag:                 MediaParser{},
 	IFrameStreamInfTag:       IFrameStreamInfParser{},
 	SessionKeyTag:            SessionKeyParser{},
+	SessionDataTag:           SessionDataParser{},
+	ContentSteeringTag:       ContentSteeringParser{},
 	IndependentSegmentsTag:   IndependentSegmentsParser{},
 	VariableDefineTag:        VariableDefineParser{},
+	StartTag:                 StartParser{},
 	USPTimestampMapTag:       USPTimestampMapParser{},
 	EventCueOutTag:           EventCueOutParser{},
 	EventCueInTag:            EventCueInParser{},
 	CommentLineTag:           CommentParser{},
+	UnknownTagTag:            UnknownTagParser{},
 }
 
-// Parse *Playlist to string.
+// Writes the HLS element of a *Playlist node in m3u8 format.
 type PlaylistEncoder interface {
-	Encode(node *internal.Node, builder *strings.Builder) error
+	Encode(node *node.Node, w io.Writer) error
 }
 
 var Encoders = map[string]PlaylistEncoder{
@@ -49,20 +63,32 @@ var Encoders = map[string]PlaylistEncoder{
 	MediaSequenceName:         MediaSequenceEncoder{},
 	DiscontinuitySequenceName: DiscontinuitySequenceEncoder{},
 	IFramesOnlyName:           IFramesOnlyEncoder{},
+	EndlistName:               EndlistEncoder{},
+	PlaylistTypeName:          PlaylistTypeEncoder{},
+	PartInfName:               PartInfEncoder{},
+	ServerControlName:         ServerControlEncoder{},
 	ProgramDateTimeName:       ProgramDateTimeEncoder{},
 	KeyName:                   KeyEncoder{},
 	MapName:                   MapEncoder{},
+	PartName:                  PartEncoder{},
 	DateRangeName:             DateRangeEncoder{},
+	PreloadHintName:           PreloadHintEncoder{},
+	SkipName:                  SkipEncoder{},
+	RenditionReportName:       RenditionReportEncoder{},
 	ExtInfName:                ExtInfEncoder{},
 	DiscontinuityName:         DiscontinuityEncoder{},
 	StreamInfName:             StreamInfEncoder{},
 	MediaName:                 MediaEncoder{},
 	IFrameStreamInfName:       IFrameStreamInfEncoder{},
 	SessionKeyName:            SessionKeyEncoder{},
+	SessionDataName:           SessionDataEncoder{},
+	ContentSteeringName:       ContentSteeringEncoder{},
 	IndependentSegmentsName:   IndependentSegmentsEncoder{},
 	VariableDefineName:        VariableDefineEncoder{},
+	StartName:                 StartEncoder{},
 	USPTimestampMapName:       USPTimestampMapEncoder{},
 	EventCueOutName:           EventCueOutEncoder{},
 	EventCueInName:            EventCueInEncoder{},
 	CommentLineName:           CommentEncoder{},
+	UnknownTagName:            UnknownTagEncoder{},
 }